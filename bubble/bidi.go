@@ -0,0 +1,118 @@
+package bubble
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// A glyph is a grapheme cluster of a text and its byte offset in the text.
+type glyph struct {
+	text string
+	pos  int
+}
+
+// visualOrder returns the grapheme clusters of a right-to-left text in the
+// order they are displayed from left to right. The text is split into runs
+// with the Unicode bidirectional algorithm. Left-to-right runs, such as
+// numbers and Latin words, keep their order, and the brackets of
+// right-to-left runs are mirrored.
+func visualOrder(s string) []glyph {
+	var p bidi.Paragraph
+	if _, err := p.SetString(s, bidi.DefaultDirection(bidi.RightToLeft)); err != nil {
+		return graphemes(s, 0)
+	}
+	o, err := p.Order()
+	if err != nil || o.NumRuns() == 0 {
+		return graphemes(s, 0)
+	}
+
+	// The positions of runs are rune indices.
+	offsets := make([]int, 0, len(s)+1)
+	for i := range s {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(s))
+
+	// A paragraph separator ends the text that is ordered.
+	last := o.Run(o.NumRuns() - 1)
+	if _, end := last.Pos(); end+1 != len(offsets)-1 {
+		return graphemes(s, 0)
+	}
+
+	glyphs := make([]glyph, 0, len(offsets))
+	for i := o.NumRuns() - 1; i >= 0; i-- {
+		run := o.Run(i)
+		start, end := run.Pos()
+		part := graphemes(s[offsets[start]:offsets[end+1]], offsets[start])
+		if run.Direction() == bidi.RightToLeft {
+			for l, r := 0, len(part)-1; l < r; l, r = l+1, r-1 {
+				part[l], part[r] = part[r], part[l]
+			}
+			for j, g := range part {
+				if utf8.RuneCountInString(g.text) == 1 {
+					part[j].text = bidi.ReverseString(g.text)
+				}
+			}
+		}
+		glyphs = append(glyphs, part...)
+	}
+	return glyphs
+}
+
+// graphemes returns the grapheme clusters of s, whose offsets start at pos.
+func graphemes(s string, pos int) []glyph {
+	var glyphs []glyph
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		start, _ := g.Positions()
+		glyphs = append(glyphs, glyph{text: g.Str(), pos: pos + start})
+	}
+	return glyphs
+}
+
+// reorder returns a right-to-left text in display order.
+func reorder(s string) string {
+	var b strings.Builder
+	for _, g := range visualOrder(s) {
+		b.WriteString(g.text)
+	}
+	return b.String()
+}
+
+// truncateLeft removes the first width columns of a rendered line, keeping
+// its escape sequences so that the rest of the line is still styled. A wide
+// grapheme cluster that is cut in half is replaced with spaces.
+func truncateLeft(s string, width int) string {
+	var b strings.Builder
+	var cut int
+	for s != "" {
+		if s[0] == '\x1b' {
+			end := strings.IndexByte(s, 'm')
+			if end < 0 {
+				end = len(s) - 1
+			}
+			b.WriteString(s[:end+1])
+			s = s[end+1:]
+			continue
+		}
+
+		end := strings.IndexByte(s, '\x1b')
+		if end < 0 {
+			end = len(s)
+		}
+		text := s[:end]
+		s = s[end:]
+
+		if cut < width {
+			w := runewidth.StringWidth(text)
+			text = runewidth.TruncateLeft(text, width-cut, "")
+			cut += w
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package bubble
+
+import (
+	"testing"
+)
+
+func TestReorder(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"it keeps left-to-right text", "Dubai", "Dubai"},
+		{"it reverses right-to-left text", "القاهرة", "ةرهاقلا"},
+		{"it keeps the order of numbers", "ميناء 2020", "2020 ءانيم"},
+		{"it mirrors brackets", "عاصمة (مصر)", "(رصم) ةمصاع"},
+		{"it keeps empty text", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reorder(tt.value); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTruncateLeft(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		width int
+		want  string
+	}{
+		{"it keeps lines without a width", "abc", 0, "abc"},
+		{"it removes columns", "abcdef", 2, "cdef"},
+		{"it keeps escape sequences", "\x1b[1mab\x1b[0mcd", 3, "\x1b[1m\x1b[0md"},
+		{"it removes columns across styles", "\x1b[1mab\x1b[0mcd", 1, "\x1b[1mb\x1b[0mcd"},
+		{"it replaces halves of wide characters", "東京", 1, " 京"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateLeft(tt.value, tt.width); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
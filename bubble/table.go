@@ -28,12 +28,83 @@ type Model struct {
 	cursorMode   cursorMode
 	focus        bool
 	styles       Styles
-
+	highlight    Highlighter
+	cellStyle    CellStyle
+
+	// borders draws lines between the columns and under the headers, zebra
+	// alternates the background of rows and compact removes the padding of
+	// cells.
+	borders bool
+	zebra   bool
+	compact bool
+
+	// wrap breaks the values of cells that are wider than their column onto
+	// several lines, and rows are as high as their tallest cell.
+	wrap bool
+
+	// rtl mirrors the table for right-to-left languages, with the first
+	// column on the right and cell text in bidirectional display order.
+	rtl bool
+
+	// gutter holds the label of each row, such as its number, shown in a
+	// column before the first column that doesn't scroll, or nil for no
+	// gutter. gutterWidth is the width of the widest label.
+	gutter      []string
+	gutterWidth int
+
+	// editor is rendered in place of the cell being edited, or nil.
+	editor *editor
+
+	// visual is the selection started with StartVisual, or nil.
+	visual *visual
+
+	// height is the height of the table under the headers, including the
+	// rule under them when borders are drawn.
+	height   int
 	viewport viewport.Model
 	start    int
 	end      int
+
+	// body holds the rendered rows, which are padded and truncated here
+	// instead of by the viewport, since lipgloss measures the width of each
+	// rune of a grapheme cluster.
+	body string
+
+	// decimals holds the width of the widest fractional part of the numbers
+	// in each column aligned with AlignDecimal.
+	decimals []int
+
+	// offset is the first column shown and colEnd is one past the last
+	// column shown, which may be cut off at the right edge.
+	offset int
+	colEnd int
+}
+
+// editor is the view of an input, such as a text input, rendered in place of
+// the value of a cell. A row of -1 is the header of the column.
+type editor struct {
+	row  int
+	col  int
+	view string
 }
 
+// visual is a selection of the cells between an anchor and the cursor. A
+// selection of rows spans every column.
+type visual struct {
+	rows bool
+	row  int
+	col  int
+}
+
+// Highlighter returns the byte ranges of a cell value to render with the Match
+// style, in the form returned by regexp.Regexp.FindAllStringIndex.
+type Highlighter func(value string) [][]int
+
+// CellStyle returns the style of the cell of a row and column, or false if the
+// cell is styled like the rest of its row. The style is combined with the
+// style of the row, which takes precedence on the selected row.
+type CellStyle func(row, col int, value string) (lipgloss.Style, bool)
+
 // Row represents one line in the table.
 type Row []string
 
@@ -41,6 +112,39 @@ type Row []string
 type Column struct {
 	Title string
 	Width int
+
+	// Indicator is shown at the end of the header, e.g. to show the sort
+	// order of the column.
+	Indicator string
+
+	Align Alignment
+}
+
+// Alignment is the horizontal alignment of the cells of a column.
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+
+	// AlignDecimal aligns numbers on their decimal separator and otherwise
+	// aligns right.
+	AlignDecimal
+)
+
+// String returns the name of the alignment.
+func (a Alignment) String() string {
+	switch a {
+	case AlignCenter:
+		return "center"
+	case AlignRight:
+		return "right"
+	case AlignDecimal:
+		return "decimal"
+	default:
+		return "left"
+	}
 }
 
 // KeyMap defines keybindings. It satisfies to the help.KeyMap interface, which
@@ -56,6 +160,19 @@ type KeyMap struct {
 	GotoBottom   key.Binding
 }
 
+// ShortHelp implements the KeyMap interface.
+func (km KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{km.LineUp, km.LineDown}
+}
+
+// FullHelp implements the KeyMap interface.
+func (km KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{km.LineUp, km.LineDown, km.GotoTop, km.GotoBottom},
+		{km.PageUp, km.PageDown, km.HalfPageUp, km.HalfPageDown},
+	}
+}
+
 // DefaultKeyMap returns a default set of keybindings.
 func DefaultKeyMap() KeyMap {
 	const spacebar = " "
@@ -98,17 +215,33 @@ func DefaultKeyMap() KeyMap {
 // Styles contains style definitions for this list component. By default, these
 // values are generated by DefaultStyles.
 type Styles struct {
-	Header   lipgloss.Style
-	Cell     lipgloss.Style
-	Selected lipgloss.Style
+	Header       lipgloss.Style
+	Cell         lipgloss.Style
+	Selected     lipgloss.Style
+	SelectedCell lipgloss.Style
+	Match        lipgloss.Style
+
+	// Border is used for the lines drawn with borders, Zebra for every other
+	// row with zebra striping, Gutter for the labels of rows and Visual for
+	// the cells selected with StartVisual.
+	Border lipgloss.Style
+	Zebra  lipgloss.Style
+	Gutter lipgloss.Style
+	Visual lipgloss.Style
 }
 
 // DefaultStyles returns a set of default style definitions for this table.
 func DefaultStyles() Styles {
 	return Styles{
-		Selected: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212")),
-		Header:   lipgloss.NewStyle().Bold(true).Padding(0, 1),
-		Cell:     lipgloss.NewStyle().Padding(0, 1),
+		Selected:     lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212")),
+		SelectedCell: lipgloss.NewStyle().Reverse(true),
+		Match:        lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("220")),
+		Header:       lipgloss.NewStyle().Bold(true).Padding(0, 1),
+		Cell:         lipgloss.NewStyle().Padding(0, 1),
+		Border:       lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+		Zebra:        lipgloss.NewStyle().Background(lipgloss.Color("235")),
+		Gutter:       lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+		Visual:       lipgloss.NewStyle().Background(lipgloss.Color("24")),
 	}
 }
 
@@ -128,6 +261,7 @@ func New(opts ...Option) Model {
 	m := Model{
 		rowCursor:  0,
 		cursorMode: rowMode,
+		height:     20,
 		viewport:   viewport.New(0, 20),
 
 		KeyMap: DefaultKeyMap(),
@@ -138,6 +272,7 @@ func New(opts ...Option) Model {
 		opt(&m)
 	}
 
+	m.updateDecimals()
 	m.UpdateViewport()
 
 	return m
@@ -160,7 +295,7 @@ func WithRows(rows []Row) Option {
 // WithHeight sets the height of the table.
 func WithHeight(h int) Option {
 	return func(m *Model) {
-		m.viewport.Height = h
+		m.height = h
 	}
 }
 
@@ -171,6 +306,49 @@ func WithWidth(w int) Option {
 	}
 }
 
+// WithBorders draws lines between the columns and under the headers.
+func WithBorders(b bool) Option {
+	return func(m *Model) {
+		m.borders = b
+	}
+}
+
+// WithZebra alternates the background of rows.
+func WithZebra(z bool) Option {
+	return func(m *Model) {
+		m.zebra = z
+	}
+}
+
+// WithCompact removes the padding of cells, leaving a single space or border
+// between columns.
+func WithCompact(c bool) Option {
+	return func(m *Model) {
+		m.compact = c
+	}
+}
+
+// WithWrap wraps the values of cells that are wider than their column.
+func WithWrap(w bool) Option {
+	return func(m *Model) {
+		m.wrap = w
+	}
+}
+
+// WithRTL lays out the table from right to left.
+func WithRTL(rtl bool) Option {
+	return func(m *Model) {
+		m.rtl = rtl
+	}
+}
+
+// WithGutter sets the labels of the rows shown in the gutter.
+func WithGutter(labels []string) Option {
+	return func(m *Model) {
+		m.SetGutter(labels)
+	}
+}
+
 // WithFocused sets the focus state of the table.
 func WithFocused(f bool) Option {
 	return func(m *Model) {
@@ -185,6 +363,13 @@ func WithStyles(s Styles) Option {
 	}
 }
 
+// WithCellStyle sets the function used to style individual cells.
+func WithCellStyle(f CellStyle) Option {
+	return func(m *Model) {
+		m.cellStyle = f
+	}
+}
+
 // WithKeyMap sets the key map.
 func WithKeyMap(km KeyMap) Option {
 	return func(m *Model) {
@@ -208,15 +393,13 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		case key.Matches(msg, m.KeyMap.LineDown):
 			m.MoveDown(1)
 		case key.Matches(msg, m.KeyMap.PageUp):
-			m.MoveUp(m.viewport.Height)
+			m.MoveUp(m.PageSize())
 		case key.Matches(msg, m.KeyMap.PageDown):
-			m.MoveDown(m.viewport.Height)
+			m.MoveDown(m.PageSize())
 		case key.Matches(msg, m.KeyMap.HalfPageUp):
-			m.MoveUp(m.viewport.Height / 2)
+			m.MoveUp(m.PageSize() / 2)
 		case key.Matches(msg, m.KeyMap.HalfPageDown):
-			m.MoveDown(m.viewport.Height / 2)
-		case key.Matches(msg, m.KeyMap.LineDown):
-			m.MoveDown(1)
+			m.MoveDown(m.PageSize() / 2)
 		case key.Matches(msg, m.KeyMap.GotoTop):
 			m.GotoTop()
 		case key.Matches(msg, m.KeyMap.GotoBottom):
@@ -247,30 +430,267 @@ func (m *Model) Blur() {
 
 // View renders the component.
 func (m Model) View() string {
-	return m.headersView() + "\n" + m.viewport.View()
+	return m.headersView() + "\n" + m.body
+}
+
+// SetBorders sets whether lines are drawn between the columns and under the
+// headers.
+func (m *Model) SetBorders(b bool) {
+	m.borders = b
+	m.UpdateViewport()
+}
+
+// Borders reports whether lines are drawn between the columns and under the
+// headers.
+func (m Model) Borders() bool {
+	return m.borders
+}
+
+// SetZebra sets whether the background of rows alternates.
+func (m *Model) SetZebra(z bool) {
+	m.zebra = z
+	m.UpdateViewport()
+}
+
+// Zebra reports whether the background of rows alternates.
+func (m Model) Zebra() bool {
+	return m.zebra
+}
+
+// SetCompact sets whether the padding of cells is removed.
+func (m *Model) SetCompact(c bool) {
+	m.compact = c
+	m.UpdateViewport()
+}
+
+// Compact reports whether the padding of cells is removed.
+func (m Model) Compact() bool {
+	return m.compact
+}
+
+// SetWrap sets whether the values of cells that are wider than their column
+// wrap onto several lines instead of being cut off.
+func (m *Model) SetWrap(w bool) {
+	m.wrap = w
+	m.UpdateViewport()
+}
+
+// Wrap reports whether the values of cells wrap.
+func (m Model) Wrap() bool {
+	return m.wrap
+}
+
+// SetRTL sets whether the table is laid out from right to left, with the
+// first column on the right and the text of cells reordered for display with
+// the Unicode bidirectional algorithm.
+func (m *Model) SetRTL(rtl bool) {
+	m.rtl = rtl
+	m.UpdateViewport()
+}
+
+// RTL reports whether the table is laid out from right to left.
+func (m Model) RTL() bool {
+	return m.rtl
+}
+
+// SetGutter sets the label of each row, such as its number, shown in a gutter
+// before the first column. The gutter stays in place when the columns
+// scroll. A nil slice removes the gutter.
+func (m *Model) SetGutter(labels []string) {
+	m.gutter = labels
+	m.gutterWidth = 0
+	for _, label := range labels {
+		m.gutterWidth = max(m.gutterWidth, runewidth.StringWidth(cellText(label)))
+	}
+	m.UpdateViewport()
+}
+
+// Gutter returns the labels of the rows shown in the gutter.
+func (m Model) Gutter() []string {
+	return m.gutter
+}
+
+// SetEditor renders view, such as the view of a text input, in place of the
+// value of a cell while it is edited. A row of -1 is the header of the
+// column. The view is cut off at the width of the column.
+func (m *Model) SetEditor(row, col int, view string) {
+	m.editor = &editor{row: row, col: col, view: view}
+	m.UpdateViewport()
+}
+
+// ClearEditor stops rendering the view set with SetEditor.
+func (m *Model) ClearEditor() {
+	m.editor = nil
+	m.UpdateViewport()
+}
+
+// StartVisual starts selecting the cells between the selected cell and the
+// cursor as it moves, or the rows between them if rows is set. A selection
+// already started keeps its first cell, and selects rows or cells instead.
+func (m *Model) StartVisual(rows bool) {
+	if m.visual == nil {
+		m.visual = &visual{row: m.rowCursor, col: m.columnCursor}
+	}
+	m.visual.rows = rows
+	m.UpdateViewport()
+}
+
+// StopVisual clears the selection started with StartVisual.
+func (m *Model) StopVisual() {
+	m.visual = nil
+	m.UpdateViewport()
+}
+
+// Visual returns "rows" or "cells" for the kind of selection started with
+// StartVisual, or "" if there is none.
+func (m Model) Visual() string {
+	switch {
+	case m.visual == nil:
+		return ""
+	case m.visual.rows:
+		return "rows"
+	default:
+		return "cells"
+	}
+}
+
+// Selection returns the first and last rows and columns of the selection
+// started with StartVisual, or false if there is none.
+func (m Model) Selection() (top, bottom, left, right int, ok bool) {
+	if m.visual == nil || len(m.rows) == 0 || len(m.cols) == 0 {
+		return 0, 0, 0, 0, false
+	}
+	row := clamp(m.visual.row, 0, len(m.rows)-1)
+	top, bottom = min(row, m.rowCursor), max(row, m.rowCursor)
+	if m.visual.rows {
+		return top, bottom, 0, len(m.cols) - 1, true
+	}
+	col := clamp(m.visual.col, 0, len(m.cols)-1)
+	return top, bottom, min(col, m.columnCursor), max(col, m.columnCursor), true
+}
+
+// inSelection reports whether the cell at row and col is selected.
+func (m Model) inSelection(row, col int) bool {
+	top, bottom, left, right, ok := m.Selection()
+	return ok && row >= top && row <= bottom && col >= left && col <= right
 }
 
 // UpdateViewport updates the list content based on the previously defined
 // columns and rows.
 func (m *Model) UpdateViewport() {
-	renderedRows := make([]string, 0, len(m.rows))
+	m.viewport.Height = max(m.height-m.headerHeight()+1, 0)
 
-	// Render only rows from: m.rowCursor-m.viewport.Height to: m.rowCursor+m.viewport.Height
-	// Constant runtime, independent of number of rows in a table.
-	// Limits the number of renderedRows to a maximum of 2*m.viewport.Height
-	if m.rowCursor >= 0 {
-		m.start = clamp(m.rowCursor-m.viewport.Height, 0, m.rowCursor)
-	} else {
-		m.start = 0
+	// Scroll columns just enough to keep the selected column fully visible,
+	// without leaving empty space after the last column. Columns come first
+	// since the height of wrapped rows depends on the columns shown.
+	if m.columnCursor < m.offset {
+		m.offset = m.columnCursor
+	}
+	for m.offset < m.columnCursor && !m.columnFits(m.offset, m.columnCursor) {
+		m.offset++
+	}
+	m.offset = clamp(m.offset, 0, m.maxOffset())
+	m.colEnd = m.columnsEnd(m.offset)
+
+	// Render only the rows that fit in the viewport, scrolling just enough to
+	// keep the cursor visible. Constant runtime, independent of number of rows
+	// in a table.
+	if m.rowCursor < m.start {
+		m.start = m.rowCursor
 	}
-	m.end = clamp(m.rowCursor+m.viewport.Height, m.rowCursor, len(m.rows))
+	if m.rowCursor >= m.rowsEnd(m.start) {
+		m.start = m.rowsStart(m.rowCursor + 1)
+	}
+	m.start = clamp(m.start, 0, m.rowsStart(len(m.rows)))
+	m.end = m.rowsEnd(m.start)
+
+	lines := make([]string, 0, m.viewport.Height)
+	var gutter []string
 	for i := m.start; i < m.end; i++ {
-		renderedRows = append(renderedRows, m.renderRow(i))
+		row := m.renderRow(i)
+		lines = append(lines, row...)
+		if m.gutter != nil {
+			gutter = append(gutter, m.renderGutter(i, len(row))...)
+		}
+	}
+	for len(lines) < m.viewport.Height {
+		lines = append(lines, strings.Repeat(" ", m.rowWidth()))
+		if m.gutter != nil {
+			gutter = append(gutter, strings.Repeat(" ", m.gutterSize()))
+		}
+	}
+	if gutter != nil {
+		gutter = gutter[:m.viewport.Height]
+	}
+	m.body = m.fitWidth(lines[:m.viewport.Height], gutter)
+}
+
+// rowsEnd returns one past the last row that fits in the viewport when the
+// rows shown start at start. The first row always fits.
+func (m Model) rowsEnd(start int) int {
+	height := max(m.viewport.Height, 1)
+	if len(m.rows) == 0 {
+		return 0
+	}
+	if !m.wrap {
+		return min(start+height, len(m.rows))
+	}
+	end := start
+	for end < len(m.rows) {
+		height -= m.rowHeight(end)
+		if height < 0 {
+			break
+		}
+		end++
+	}
+	return max(end, min(start+1, len(m.rows)))
+}
+
+// rowsStart returns the first row of the rows that fit in the viewport and
+// end before end. The last row always fits.
+func (m Model) rowsStart(end int) int {
+	height := max(m.viewport.Height, 1)
+	if len(m.rows) == 0 {
+		return 0
+	}
+	if !m.wrap {
+		return max(end-height, 0)
 	}
+	end = min(end, len(m.rows))
+	start := end
+	for start > 0 {
+		height -= m.rowHeight(start - 1)
+		if height < 0 {
+			break
+		}
+		start--
+	}
+	return max(min(start, end-1), 0)
+}
 
-	m.viewport.SetContent(
-		lipgloss.JoinVertical(lipgloss.Left, renderedRows...),
-	)
+// rowHeight returns the number of lines of a row. In wrap mode it is the
+// number of lines of its tallest cell in the columns shown, up to the height
+// of the viewport.
+func (m Model) rowHeight(row int) int {
+	if !m.wrap {
+		return 1
+	}
+	height := 1
+	for i := m.offset; i < m.colEnd; i++ {
+		if i < len(m.rows[row]) {
+			height = max(height, len(wrapText(cellText(m.rows[row][i]), m.cols[i].Width)))
+		}
+	}
+	return min(height, max(m.viewport.Height, 1))
+}
+
+// PageSize returns the number of rows moved by a page, which is the number of
+// rows shown.
+func (m Model) PageSize() int {
+	if !m.wrap {
+		return m.viewport.Height
+	}
+	return max(m.end-m.start, 1)
 }
 
 // SelectedRow returns the selected row.
@@ -287,6 +707,8 @@ func (m Model) Rows() []Row {
 // SetRows sets a new rows state.
 func (m *Model) SetRows(r []Row) {
 	m.rows = r
+	m.rowCursor = clamp(m.rowCursor, 0, max(len(r)-1, 0))
+	m.updateDecimals()
 	m.UpdateViewport()
 }
 
@@ -297,6 +719,8 @@ func (m *Model) Columns() []Column {
 // SetColumns sets a new columns state.
 func (m *Model) SetColumns(c []Column) {
 	m.cols = c
+	m.columnCursor = clamp(m.columnCursor, 0, max(len(c)-1, 0))
+	m.updateDecimals()
 	m.UpdateViewport()
 }
 
@@ -306,15 +730,15 @@ func (m *Model) SetWidth(w int) {
 	m.UpdateViewport()
 }
 
-// SetHeight sets the height of the viewport of the table.
+// SetHeight sets the height of the table under the headers.
 func (m *Model) SetHeight(h int) {
-	m.viewport.Height = h
+	m.height = h
 	m.UpdateViewport()
 }
 
-// Height returns the viewport height of the table.
+// Height returns the height of the table under the headers.
 func (m Model) Height() int {
-	return m.viewport.Height
+	return m.height
 }
 
 // Width returns the viewport width of the table.
@@ -334,13 +758,51 @@ func (m Model) Cursor() int {
 	}
 }
 
+// RowCursor returns the index of the selected row regardless of the cursor mode.
+func (m Model) RowCursor() int {
+	return m.rowCursor
+}
+
+// ColumnCursor returns the index of the selected column regardless of the
+// cursor mode.
+func (m Model) ColumnCursor() int {
+	return m.columnCursor
+}
+
+// SelectCell moves the row and column cursors to the given cell regardless of
+// the cursor mode.
+func (m *Model) SelectCell(row, col int) {
+	m.rowCursor = clamp(row, 0, max(len(m.rows)-1, 0))
+	m.columnCursor = clamp(col, 0, max(len(m.cols)-1, 0))
+	m.UpdateViewport()
+}
+
+// SetHighlight sets the function used to find the parts of cells to
+// highlight. A nil Highlighter turns highlighting off.
+func (m *Model) SetHighlight(h Highlighter) {
+	m.highlight = h
+	m.UpdateViewport()
+}
+
+// SetCellStyle sets the function used to style individual cells. A nil
+// CellStyle styles cells like their row.
+func (m *Model) SetCellStyle(f CellStyle) {
+	m.cellStyle = f
+	m.UpdateViewport()
+}
+
+// CellStyle returns the function used to style individual cells.
+func (m Model) CellStyle() CellStyle {
+	return m.cellStyle
+}
+
 // SetCursor sets the cursor position in the table.
 func (m *Model) SetCursor(n int) {
 	switch m.cursorMode {
 	case rowMode:
-		m.rowCursor = clamp(n, 0, len(m.rows)-1)
+		m.rowCursor = clamp(n, 0, max(len(m.rows)-1, 0))
 	case columnMode:
-		m.columnCursor = n
+		m.columnCursor = clamp(n, 0, max(len(m.cols)-1, 0))
 	}
 	m.UpdateViewport()
 }
@@ -364,22 +826,14 @@ func (m *Model) SwitchCursorMode() {
 func (m *Model) MoveUp(n int) {
 	switch m.cursorMode {
 	case rowMode:
-		m.rowCursor = clamp(m.rowCursor-n, 0, len(m.rows)-1)
-		switch {
-		case m.start == 0:
-			m.viewport.SetYOffset(clamp(m.viewport.YOffset, 0, m.rowCursor))
-		case m.start < m.viewport.Height:
-			m.viewport.SetYOffset(clamp(m.viewport.YOffset+n, 0, m.rowCursor))
-		case m.viewport.YOffset >= 1:
-			m.viewport.YOffset = clamp(m.viewport.YOffset+n, 1, m.viewport.Height)
-		}
-		m.UpdateViewport()
+		m.rowCursor = clamp(m.rowCursor-n, 0, max(len(m.rows)-1, 0))
 	case columnMode:
 		m.columnCursor--
 		if m.columnCursor < 0 {
-			m.columnCursor = len(m.Columns()) - 1
+			m.columnCursor = max(len(m.Columns())-1, 0)
 		}
 	}
+	m.UpdateViewport()
 }
 
 // MoveDown moves the selection down by any number of rows.
@@ -387,34 +841,258 @@ func (m *Model) MoveUp(n int) {
 func (m *Model) MoveDown(n int) {
 	switch m.cursorMode {
 	case rowMode:
-		m.rowCursor = clamp(m.rowCursor+n, 0, len(m.rows)-1)
-		m.UpdateViewport()
-
-		switch {
-		case m.end == len(m.rows):
-			m.viewport.SetYOffset(clamp(m.viewport.YOffset-n, 1, m.viewport.Height))
-		case m.rowCursor > (m.end-m.start)/2:
-			m.viewport.SetYOffset(clamp(m.viewport.YOffset-n, 1, m.rowCursor))
-		case m.viewport.YOffset > 1:
-		case m.rowCursor > m.viewport.YOffset+m.viewport.Height-1:
-			m.viewport.SetYOffset(clamp(m.viewport.YOffset+1, 0, 1))
-		}
+		m.rowCursor = clamp(m.rowCursor+n, 0, max(len(m.rows)-1, 0))
 	case columnMode:
 		m.columnCursor++
 		if m.columnCursor >= len(m.Columns()) {
 			m.columnCursor = 0
 		}
 	}
+	m.UpdateViewport()
+}
+
+// MoveLeft moves the selected cell left by any number of columns.
+// It can not go before the first column, which is on the right of a
+// right-to-left table.
+func (m *Model) MoveLeft(n int) {
+	m.MoveRight(-n)
+}
+
+// MoveRight moves the selected cell right by any number of columns.
+// It can not go past the last column, which is on the left of a right-to-left
+// table.
+func (m *Model) MoveRight(n int) {
+	if m.rtl {
+		n = -n
+	}
+	m.columnCursor = clamp(m.columnCursor+n, 0, max(len(m.cols)-1, 0))
+	m.UpdateViewport()
 }
 
-// GotoTop moves the selection to the first row.
+// GotoTop moves the selection to the first row, or to the first column in
+// column mode.
 func (m *Model) GotoTop() {
-	m.MoveUp(m.rowCursor)
+	m.SetCursor(0)
 }
 
-// GotoBottom moves the selection to the last row.
+// GotoBottom moves the selection to the last row, or to the last column in
+// column mode.
 func (m *Model) GotoBottom() {
-	m.MoveDown(len(m.rows))
+	switch m.cursorMode {
+	case rowMode:
+		m.SetCursor(len(m.rows) - 1)
+	case columnMode:
+		m.SetCursor(len(m.cols) - 1)
+	}
+}
+
+// ScrollUp scrolls the rows up by n rows, moving the cursor if it would
+// leave the viewport.
+func (m *Model) ScrollUp(n int) {
+	m.ScrollDown(-n)
+}
+
+// ScrollDown scrolls the rows down by n rows, moving the cursor if it would
+// leave the viewport.
+func (m *Model) ScrollDown(n int) {
+	m.start = clamp(m.start+n, 0, m.rowsStart(len(m.rows)))
+	m.rowCursor = clamp(m.rowCursor, m.start, m.rowsEnd(m.start)-1)
+	m.UpdateViewport()
+}
+
+// ScrollLeft scrolls the columns left by n columns, moving the cursor if it
+// would leave the viewport.
+func (m *Model) ScrollLeft(n int) {
+	m.ScrollRight(-n)
+}
+
+// ScrollRight scrolls the columns right by n columns, moving the cursor if it
+// would leave the viewport.
+func (m *Model) ScrollRight(n int) {
+	if m.rtl {
+		n = -n
+	}
+	m.offset = clamp(m.offset+n, 0, m.maxOffset())
+	last := m.offset
+	for last+1 < len(m.cols) && m.columnFits(m.offset, last+1) {
+		last++
+	}
+	m.columnCursor = clamp(m.columnCursor, m.offset, last)
+	m.UpdateViewport()
+}
+
+// RowAt returns the index of the row shown on line y of the table, where the
+// headers are on line 0, or -1 if there is none.
+func (m Model) RowAt(y int) int {
+	if y < m.headerHeight() {
+		return -1
+	}
+	line := y - m.headerHeight()
+	for row := m.start; row < m.end; row++ {
+		line -= m.rowHeight(row)
+		if line < 0 {
+			return row
+		}
+	}
+	return -1
+}
+
+// ColumnAt returns the index of the column shown at x, or -1 if there is none.
+func (m Model) ColumnAt(x int) int {
+	x = m.logicalX(x)
+	if x < 0 {
+		return -1
+	}
+	var pos int
+	for i := m.offset; i < m.colEnd; i++ {
+		pos += m.columnWidth(i)
+		if x < pos {
+			return i
+		}
+	}
+	return -1
+}
+
+// BorderAt returns the index of the column whose right border is at x, or -1
+// if there is none. The border is the padding on either side of the edge
+// between two columns. In a right-to-left table it is the left border.
+func (m Model) BorderAt(x int) int {
+	x = m.logicalX(x)
+	var pos int
+	for i := m.offset; i < m.colEnd; i++ {
+		pos += m.columnWidth(i)
+		if x == pos-1 || x == pos {
+			return i
+		}
+	}
+	return -1
+}
+
+// FrameWidth returns the width taken by the gutter, the padding of the cells
+// and the separators between n columns, which is the width of a row of n
+// columns besides their content.
+func (m Model) FrameWidth(n int) int {
+	if n <= 0 {
+		return m.gutterSize()
+	}
+	left, right := m.padding()
+	return m.gutterSize() + n*(left+right) + (n-1)*runewidth.StringWidth(m.separator())
+}
+
+// logicalX returns the distance of x from the start of the columns shown,
+// which is the right edge of a right-to-left table. It is negative if x is
+// on the gutter.
+func (m Model) logicalX(x int) int {
+	if !m.rtl {
+		return x - m.gutterSize()
+	}
+	width := m.rowWidth()
+	if m.viewport.Width > 0 {
+		width = m.columnsWidth()
+	}
+	return width - 1 - x
+}
+
+// gutterSize returns the width of the gutter, including its padding and the
+// separator after it, or 0 if there is no gutter.
+func (m Model) gutterSize() int {
+	if m.gutter == nil {
+		return 0
+	}
+	left, right := m.padding()
+	return left + m.gutterWidth + right + runewidth.StringWidth(m.separator())
+}
+
+// columnsWidth returns the width left for the columns next to the gutter, or
+// 0 if the width of the table isn't set.
+func (m Model) columnsWidth() int {
+	if m.viewport.Width <= 0 {
+		return 0
+	}
+	return max(m.viewport.Width-m.gutterSize(), 1)
+}
+
+// columnWidth returns the width of a rendered column, including its padding
+// and the separator after it.
+func (m Model) columnWidth(col int) int {
+	left, right := m.padding()
+	width := left + m.cols[col].Width + right
+	if col < len(m.cols)-1 {
+		width += runewidth.StringWidth(m.separator())
+	}
+	return width
+}
+
+// padding returns the padding on the left and right of cells.
+func (m Model) padding() (int, int) {
+	if m.compact {
+		return 0, 0
+	}
+	return m.styles.Cell.GetPaddingLeft(), m.styles.Cell.GetPaddingRight()
+}
+
+// separator returns the text between two columns.
+func (m Model) separator() string {
+	switch {
+	case m.borders:
+		return "│"
+	case m.compact:
+		return " "
+	default:
+		return ""
+	}
+}
+
+// headerHeight returns the number of lines above the rows.
+func (m Model) headerHeight() int {
+	if m.borders {
+		return 2
+	}
+	return 1
+}
+
+// columnFits reports whether col is fully visible when the columns shown
+// start at offset. The first column shown always fits.
+func (m Model) columnFits(offset, col int) bool {
+	if m.viewport.Width <= 0 || col <= offset {
+		return true
+	}
+	var width int
+	for i := offset; i <= col; i++ {
+		width += m.columnWidth(i)
+	}
+	return width <= m.columnsWidth()
+}
+
+// columnsEnd returns one past the last column that is at least partly visible
+// when the columns shown start at offset.
+func (m Model) columnsEnd(offset int) int {
+	if m.viewport.Width <= 0 {
+		return len(m.cols)
+	}
+	var width int
+	for i := offset; i < len(m.cols); i++ {
+		if width >= m.columnsWidth() {
+			return i
+		}
+		width += m.columnWidth(i)
+	}
+	return len(m.cols)
+}
+
+// maxOffset returns the smallest offset that shows all the remaining columns.
+func (m Model) maxOffset() int {
+	if m.viewport.Width <= 0 {
+		return 0
+	}
+	var width int
+	for i := len(m.cols) - 1; i >= 0; i-- {
+		width += m.columnWidth(i)
+		if width > m.columnsWidth() {
+			return min(i+1, len(m.cols)-1)
+		}
+	}
+	return 0
 }
 
 // FromValues create the table rows from a simple string. It uses `\n` by
@@ -434,35 +1112,506 @@ func (m *Model) FromValues(value, separator string) {
 }
 
 func (m Model) headersView() string {
-	var s = make([]string, 0, len(m.cols))
-	for i, col := range m.cols {
-		style := lipgloss.NewStyle().Width(col.Width).MaxWidth(col.Width).Inline(true)
-		renderedCell := style.Render(runewidth.Truncate(col.Title, col.Width, "…"))
+	header := m.styles.Header.Copy()
+	if m.compact {
+		header = header.UnsetPaddingLeft().UnsetPaddingRight()
+	}
+	left, right := m.padding()
 
-		if m.cursorMode == columnMode && i == m.columnCursor {
+	titles := m.joinColumns(renderSegment(m.styles.Border, m.separator()), func(i int) string {
+		col := m.cols[i]
+		title := runewidth.Truncate(cellText(col.Title), col.Width, "…")
+		if col.Indicator != "" {
+			title = runewidth.Truncate(cellText(col.Title), col.Width-runewidth.StringWidth(col.Indicator)-1, "…")
+		}
+		if m.rtl {
+			title = reorder(title)
+		}
+		switch {
+		case col.Indicator == "":
+		case m.rtl:
+			title = col.Indicator + " " + title
+		default:
+			title += " " + col.Indicator
+		}
+		title = runewidth.Truncate(title, col.Width, "")
+
+		// Pad the title by its display width, since lipgloss measures the
+		// width of each rune of a grapheme cluster.
+		fill := max(col.Width-runewidth.StringWidth(title), 0)
+		switch m.align(i) {
+		case AlignCenter:
+			title = strings.Repeat(" ", fill/2) + title + strings.Repeat(" ", fill-fill/2)
+		case AlignRight, AlignDecimal:
+			title = strings.Repeat(" ", fill) + title
+		default:
+			title += strings.Repeat(" ", fill)
+		}
+		renderedCell := title
+
+		switch {
+		case m.editing(-1, i):
+			renderedCell = m.editorLine(i)
+		case m.cursorMode == columnMode && i == m.columnCursor:
 			renderedCell = m.styles.Selected.Render(renderedCell)
 		}
 
-		s = append(s, m.styles.Header.Render(renderedCell))
+		return header.Render(renderedCell)
+	})
+
+	lines := []string{titles}
+	var gutter []string
+	if m.gutter != nil {
+		gutter = []string{m.gutterCell(strings.Repeat(" ", m.gutterSize()-runewidth.StringWidth(m.separator())), renderSegment(m.styles.Border, m.separator()))}
+	}
+	if m.borders {
+		rule := m.joinColumns("┼", func(i int) string {
+			return strings.Repeat("─", left+m.cols[i].Width+right)
+		})
+		lines = append(lines, renderSegment(m.styles.Border, rule))
+		if m.gutter != nil {
+			gutter = append(gutter, m.gutterCell(renderSegment(m.styles.Border, strings.Repeat("─", left+m.gutterWidth+right)), renderSegment(m.styles.Border, "┼")))
+		}
+	}
+	return m.fitWidth(lines, gutter)
+}
+
+// rowWidth returns the display width of the columns shown.
+func (m Model) rowWidth() int {
+	var width int
+	for i := m.offset; i < m.colEnd; i++ {
+		width += m.columnWidth(i)
+	}
+	return width
+}
+
+// fitWidth joins lines that are rowWidth wide, truncating them if the columns
+// shown are wider than the viewport, and puts the lines of the gutter, if
+// any, before them. The lines of a right-to-left table are aligned to the
+// right edge of the viewport, so they are truncated or padded on the left,
+// and the gutter is on the right.
+func (m Model) fitWidth(lines, gutter []string) string {
+	overflow := m.rowWidth() - m.columnsWidth()
+	switch {
+	case m.viewport.Width <= 0:
+	case m.rtl && overflow > 0:
+		for i, line := range lines {
+			lines[i] = truncateLeft(line, overflow)
+		}
+	case m.rtl && overflow < 0:
+		for i, line := range lines {
+			lines[i] = strings.Repeat(" ", -overflow) + line
+		}
+	case overflow > 0:
+		truncate := lipgloss.NewStyle().MaxWidth(m.columnsWidth())
+		for i, line := range lines {
+			lines[i] = truncate.Render(line)
+		}
+	}
+	for i := range gutter {
+		if m.rtl {
+			lines[i] += gutter[i]
+		} else {
+			lines[i] = gutter[i] + lines[i]
+		}
 	}
-	return lipgloss.JoinHorizontal(lipgloss.Left, s...)
+	return strings.Join(lines, "\n")
 }
 
-func (m *Model) renderRow(rowID int) string {
-	var s = make([]string, 0, len(m.cols))
-	for i, value := range m.rows[rowID] {
-		style := lipgloss.NewStyle().Width(m.cols[i].Width).MaxWidth(m.cols[i].Width).Inline(true)
-		renderedCell := m.styles.Cell.Render(style.Render(runewidth.Truncate(value, m.cols[i].Width, "…")))
-		s = append(s, renderedCell)
+// renderGutter renders the lines of the gutter next to a row of height lines.
+// The label of the row is on its first line.
+func (m *Model) renderGutter(rowID, height int) []string {
+	base := m.rowStyle(rowID)
+	style := base.Copy().Inherit(m.styles.Gutter)
+	separator := renderSegment(m.styles.Border.Copy().Inherit(base), m.separator())
+	left, right := m.padding()
+
+	var label string
+	if rowID < len(m.gutter) {
+		label = runewidth.Truncate(cellText(m.gutter[rowID]), m.gutterWidth, "")
+	}
+	lines := make([]string, height)
+	for i := range lines {
+		text := strings.Repeat(" ", left+m.gutterWidth+right)
+		if i == 0 {
+			// Labels are aligned right, like numbers.
+			text = strings.Repeat(" ", left+m.gutterWidth-runewidth.StringWidth(label)) + label + strings.Repeat(" ", right)
+		}
+		lines[i] = m.gutterCell(renderSegment(style, text), separator)
 	}
+	return lines
+}
 
-	row := lipgloss.JoinHorizontal(lipgloss.Left, s...)
+// gutterCell joins a line of the gutter with the separator between the
+// gutter and the columns, which is on its left in a right-to-left table.
+func (m Model) gutterCell(text, separator string) string {
+	if m.rtl {
+		return separator + text
+	}
+	return text + separator
+}
 
+// rowStyle returns the style of a row, without padding.
+func (m Model) rowStyle(rowID int) lipgloss.Style {
+	base := lipgloss.NewStyle().Inherit(m.styles.Cell)
+	if m.zebra && rowID%2 == 1 {
+		base = m.styles.Zebra.Copy().Inherit(base)
+	}
 	if m.cursorMode == rowMode && rowID == m.rowCursor {
-		row = m.styles.Selected.Render(row)
+		base = m.styles.Selected.Copy().Inherit(base)
+	}
+	return base
+}
+
+// renderRow renders the lines of a row.
+func (m *Model) renderRow(rowID int) []string {
+	selected := m.cursorMode == rowMode && rowID == m.rowCursor
+
+	base := m.rowStyle(rowID)
+	separator := renderSegment(m.styles.Border.Copy().Inherit(base), m.separator())
+
+	row := m.rows[rowID]
+	height := m.rowHeight(rowID)
+
+	cells := make(map[int][]string, m.colEnd-m.offset)
+	for i := m.offset; i < m.colEnd; i++ {
+		var value string
+		if i < len(row) {
+			value = row[i]
+		}
+
+		style := base
+		if m.cellStyle != nil {
+			if cell, ok := m.cellStyle(rowID, i, value); ok {
+				if selected {
+					style = base.Copy().Inherit(cell)
+				} else {
+					style = cell.Copy().Inherit(base)
+				}
+			}
+		}
+		if m.inSelection(rowID, i) {
+			style = m.styles.Visual.Copy().Inherit(style)
+		}
+		if selected && i == m.columnCursor {
+			style = m.styles.SelectedCell.Copy().Inherit(style)
+		}
+		if m.editing(rowID, i) {
+			cells[i] = m.renderEditor(i, style, height)
+			continue
+		}
+		cells[i] = m.renderCell(value, i, style, height)
+	}
+
+	lines := make([]string, height)
+	for l := range lines {
+		lines[l] = m.joinColumns(separator, func(col int) string {
+			return cells[col][l]
+		})
 	}
+	return lines
+}
 
-	return row
+// joinColumns joins the columns shown, rendered by render, with separators in
+// between. The columns of a right-to-left table are joined from right to left.
+func (m Model) joinColumns(separator string, render func(col int) string) string {
+	var b strings.Builder
+	for i := m.offset; i < m.colEnd; i++ {
+		col := i
+		if m.rtl {
+			col = m.colEnd - 1 - (i - m.offset)
+			if col < len(m.cols)-1 {
+				b.WriteString(separator)
+			}
+		}
+		b.WriteString(render(col))
+		if !m.rtl && col < len(m.cols)-1 {
+			b.WriteString(separator)
+		}
+	}
+	return b.String()
+}
+
+// editing reports whether the cell at row and col is edited.
+func (m Model) editing(row, col int) bool {
+	return m.editor != nil && m.editor.row == row && m.editor.col == col
+}
+
+// editorLine returns the view of the editor of a column, cut off or padded to
+// the width of the column.
+func (m Model) editorLine(col int) string {
+	width := m.cols[col].Width
+	view := m.editor.view
+	if lipgloss.Width(view) > width {
+		view = lipgloss.NewStyle().MaxWidth(width).Render(view)
+	}
+	return view + strings.Repeat(" ", max(width-lipgloss.Width(view), 0))
+}
+
+// renderEditor renders the lines of a padded cell that is edited. The editor
+// is on the first line.
+func (m *Model) renderEditor(col int, style lipgloss.Style, height int) []string {
+	left, right := m.padding()
+	lines := make([]string, height)
+	for i := range lines {
+		if i == 0 {
+			lines[i] = renderSegment(style, strings.Repeat(" ", left)) + m.editorLine(col) + renderSegment(style, strings.Repeat(" ", right))
+			continue
+		}
+		lines[i] = renderSegment(style, strings.Repeat(" ", left+m.cols[col].Width+right))
+	}
+	return lines
+}
+
+// A cellLine is a line of text of a cell. pos is the offset of the text in
+// the cell value and visible the length of the text taken from the value,
+// which is followed by an ellipsis if the value is cut off.
+type cellLine struct {
+	text    string
+	pos     int
+	visible int
+}
+
+// renderCell renders the lines of a padded cell value. The value is cut off
+// with an ellipsis if it is wider than its column, or in wrap mode if it
+// needs more lines than height.
+func (m *Model) renderCell(value string, col int, style lipgloss.Style, height int) []string {
+	width := m.cols[col].Width
+	value = cellText(value)
+
+	spans := []span{{0, len(value)}}
+	if m.wrap {
+		spans = wrapText(value, width)
+	}
+	lines := make([]cellLine, height)
+	for i := range lines {
+		switch {
+		case i >= len(spans):
+		case i == height-1 && (!m.wrap || len(spans) > height):
+			// The last line holds the rest of the value.
+			lines[i] = truncateLine(value, spans[i].start, width)
+		default:
+			s := spans[i]
+			lines[i] = cellLine{text: value[s.start:s.end], pos: s.start, visible: s.end - s.start}
+		}
+	}
+
+	var matches [][]int
+	if m.highlight != nil {
+		matches = m.highlight(value)
+	}
+
+	rendered := make([]string, height)
+	for i, line := range lines {
+		rendered[i] = m.renderLine(line, col, shiftRanges(matches, line.pos), style)
+	}
+	return rendered
+}
+
+// truncateLine returns the line of the value from pos to its end, cut off
+// with an ellipsis if it is wider than width.
+func truncateLine(value string, pos, width int) cellLine {
+	rest := value[pos:]
+	text := runewidth.Truncate(rest, width, "…")
+	visible := len(text)
+	if text != rest {
+		visible = len(strings.TrimSuffix(text, "…"))
+	}
+	return cellLine{text: text, pos: pos, visible: visible}
+}
+
+// shiftRanges returns byte ranges moved back by pos, dropping those that end
+// before it.
+func shiftRanges(ranges [][]int, pos int) [][]int {
+	if pos == 0 || ranges == nil {
+		return ranges
+	}
+	shifted := make([][]int, 0, len(ranges))
+	for _, r := range ranges {
+		if r[1] > pos {
+			shifted = append(shifted, []int{max(r[0]-pos, 0), r[1] - pos})
+		}
+	}
+	return shifted
+}
+
+// renderLine renders a padded line of a cell. Each part of the line is
+// rendered on its own with a complete style instead of nesting styled
+// strings, since the reset at the end of an inner style would also end the
+// outer one.
+func (m *Model) renderLine(line cellLine, col int, matches [][]int, style lipgloss.Style) string {
+	width := m.cols[col].Width
+	text, visible := line.text, line.visible
+
+	left, right := m.padding()
+	before, after := m.alignFill(col, text, width-runewidth.StringWidth(text))
+	left += before
+	right += after
+
+	var b strings.Builder
+	b.WriteString(renderSegment(style, strings.Repeat(" ", left)))
+
+	if m.rtl {
+		b.WriteString(m.renderBidi(text, visible, matches, style))
+		b.WriteString(renderSegment(style, strings.Repeat(" ", right)))
+		return b.String()
+	}
+
+	var pos int
+	if matches != nil {
+		match := m.styles.Match.Copy().Inherit(style)
+		for _, r := range matches {
+			start, end := r[0], min(r[1], visible)
+			if start < pos || start >= end {
+				continue
+			}
+			b.WriteString(renderSegment(style, text[pos:start]))
+			b.WriteString(renderSegment(match, text[start:end]))
+			pos = end
+		}
+	}
+
+	b.WriteString(renderSegment(style, text[pos:]+strings.Repeat(" ", right)))
+
+	return b.String()
+}
+
+// renderBidi renders a right-to-left text in display order. The parts of the
+// text before visible that are in matches are rendered with the Match style.
+func (m *Model) renderBidi(text string, visible int, matches [][]int, style lipgloss.Style) string {
+	match := m.styles.Match.Copy().Inherit(style)
+
+	var b, part strings.Builder
+	var highlighted bool
+	flush := func() {
+		s := style
+		if highlighted {
+			s = match
+		}
+		b.WriteString(renderSegment(s, part.String()))
+		part.Reset()
+	}
+	for _, g := range visualOrder(text) {
+		h := g.pos < visible && inRanges(matches, g.pos)
+		if h != highlighted {
+			flush()
+			highlighted = h
+		}
+		part.WriteString(g.text)
+	}
+	flush()
+	return b.String()
+}
+
+// inRanges reports whether pos is in one of the byte ranges.
+func inRanges(ranges [][]int, pos int) bool {
+	for _, r := range ranges {
+		if pos >= r[0] && pos < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// Width returns the display width of a cell value as it is rendered, where
+// a grapheme cluster such as an emoji sequence or a letter with combining
+// marks is measured as a whole.
+func Width(value string) int {
+	return runewidth.StringWidth(cellText(value))
+}
+
+// cellText replaces the line breaks and tabs of a cell value with spaces, so
+// that it is rendered on one line with a known width.
+func cellText(value string) string {
+	return cellReplacer.Replace(value)
+}
+
+var cellReplacer = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ")
+
+// align returns the alignment of col, where left and right are swapped in a
+// right-to-left table so that text is aligned to its start.
+func (m *Model) align(col int) Alignment {
+	a := m.cols[col].Align
+	if m.rtl {
+		switch a {
+		case AlignLeft:
+			return AlignRight
+		case AlignRight:
+			return AlignLeft
+		}
+	}
+	return a
+}
+
+// alignFill splits the fill of a cell of col between the left and the right
+// of its text according to the alignment of the column.
+func (m *Model) alignFill(col int, text string, fill int) (int, int) {
+	fill = max(fill, 0)
+	switch m.align(col) {
+	case AlignCenter:
+		return fill / 2, fill - fill/2
+	case AlignRight:
+		return fill, 0
+	case AlignDecimal:
+		after := clamp(m.decimals[col]-fractionWidth(text), 0, fill)
+		return fill - after, after
+	default:
+		return 0, fill
+	}
+}
+
+// updateDecimals measures the fractional parts of the numbers in the columns
+// aligned with AlignDecimal.
+func (m *Model) updateDecimals() {
+	m.decimals = make([]int, len(m.cols))
+	for i, col := range m.cols {
+		if col.Align != AlignDecimal {
+			continue
+		}
+		for _, row := range m.rows {
+			if i < len(row) {
+				m.decimals[i] = max(m.decimals[i], fractionWidth(row[i]))
+			}
+		}
+	}
+}
+
+// fractionWidth returns the width of a number from its decimal separator to
+// its end, or 0 if it has no decimal separator. A '.' or ',' is a decimal
+// separator if it is the last of the two used, or if it is used once and,
+// for a ',', isn't followed by three digits.
+func fractionWidth(s string) int {
+	s = strings.TrimSpace(s)
+	dot, comma := strings.LastIndex(s, "."), strings.LastIndex(s, ",")
+	sep := max(dot, comma)
+
+	switch {
+	case sep < 0 || sep == len(s)-1:
+		return 0
+	case comma < 0 && strings.Count(s, ".") > 1:
+		return 0
+	case dot < 0 && (strings.Count(s, ",") > 1 || countDigits(s[sep+1:]) == 3):
+		return 0
+	case countDigits(s[sep+1:]) == 0:
+		return 0
+	}
+	return runewidth.StringWidth(s[sep:])
+}
+
+// countDigits returns the number of ASCII digits at the start of s.
+func countDigits(s string) int {
+	n := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	if n < 0 {
+		return len(s)
+	}
+	return n
+}
+
+func renderSegment(style lipgloss.Style, s string) string {
+	if s == "" {
+		return ""
+	}
+	return style.Render(s)
 }
 
 func max(a, b int) int {
@@ -0,0 +1,137 @@
+package bubble
+
+import (
+	"testing"
+)
+
+func TestWrapRows(t *testing.T) {
+	columns := []Column{{Title: "name", Width: 4}, {Title: "notes", Width: 6}}
+	rows := []Row{
+		{"a", "one two three"},
+		{"b", "four"},
+		{"c", "five six seven"},
+	}
+
+	t.Run("it renders a wrapped table without rows", func(t *testing.T) {
+		m := New(WithColumns(columns), WithWrap(true), WithHeight(5), WithWidth(20))
+		m.SetRows(nil)
+		if m.RowCursor() != 0 {
+			t.Errorf("expected row cursor 0, got %d", m.RowCursor())
+		}
+		_ = m.View()
+	})
+
+	t.Run("it turns wrap on without rows", func(t *testing.T) {
+		m := New(WithColumns(columns), WithHeight(5), WithWidth(20))
+		m.SetWrap(true)
+		_ = m.View()
+	})
+
+	t.Run("it keeps the cursor on the rows left", func(t *testing.T) {
+		m := New(WithColumns(columns), WithRows(rows), WithWrap(true), WithHeight(5), WithWidth(20))
+		m.GotoBottom()
+		m.SetRows(rows[:2])
+		if m.RowCursor() != 1 {
+			t.Errorf("expected row cursor 1, got %d", m.RowCursor())
+		}
+		m.SetRows(nil)
+		if m.RowCursor() != 0 {
+			t.Errorf("expected row cursor 0, got %d", m.RowCursor())
+		}
+		m.SetRows(rows)
+		if m.RowCursor() != 0 {
+			t.Errorf("expected row cursor 0, got %d", m.RowCursor())
+		}
+	})
+}
+
+func TestColumnCursor(t *testing.T) {
+	t.Run("it keeps the cursor at 0 without columns", func(t *testing.T) {
+		m := New(WithHeight(5), WithWidth(20))
+		m.MoveRight(1)
+		if m.ColumnCursor() != 0 {
+			t.Errorf("expected column cursor 0, got %d", m.ColumnCursor())
+		}
+		m.MoveLeft(1)
+		if m.ColumnCursor() != 0 {
+			t.Errorf("expected column cursor 0, got %d", m.ColumnCursor())
+		}
+		m.SelectCell(0, 3)
+		if m.ColumnCursor() != 0 {
+			t.Errorf("expected column cursor 0, got %d", m.ColumnCursor())
+		}
+	})
+}
+
+func TestRowHeight(t *testing.T) {
+	columns := []Column{{Title: "name", Width: 5}, {Title: "notes", Width: 5}}
+
+	tests := []struct {
+		name   string
+		row    Row
+		wrap   bool
+		height int
+		want   int
+	}{
+		{"it is one line without wrap", Row{"a", "hello world foo"}, false, 10, 1},
+		{"it is as high as the tallest cell", Row{"a", "hello world foo"}, true, 10, 3},
+		{"it is one line for short cells", Row{"a", "b"}, true, 10, 1},
+		{"it is one line for missing cells", Row{"a"}, true, 10, 1},
+		{"it is at most as high as the viewport", Row{"a", "hello world foo"}, true, 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(WithColumns(columns), WithRows([]Row{tt.row}), WithWrap(tt.wrap), WithHeight(tt.height), WithWidth(40))
+			if got := m.rowHeight(0); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFrameWidth(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		columns int
+		want    int
+	}{
+		{"it pads columns", nil, 3, 6},
+		{"it adds borders between columns", []Option{WithBorders(true)}, 3, 8},
+		{"it separates compact columns with spaces", []Option{WithCompact(true)}, 3, 2},
+		{"it draws borders between compact columns", []Option{WithCompact(true), WithBorders(true)}, 3, 2},
+		{"it adds the gutter", []Option{WithGutter([]string{"1", "10"})}, 3, 10},
+		{"it is the gutter without columns", []Option{WithGutter([]string{"1", "10"}), WithBorders(true)}, 0, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(tt.opts...)
+			if got := m.FrameWidth(tt.columns); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"abc", 3},
+		{"東京", 4},
+		{"👨‍👩‍👧", 2},
+		{"a\tb", 3},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := Width(tt.value); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package bubble
+
+import (
+	"github.com/mattn/go-runewidth"
+)
+
+// A span is a line of a wrapped text, from the byte offset start to end.
+type span struct {
+	start, end int
+}
+
+// wrapText breaks s into lines at most width wide. Lines are broken between
+// words where possible, and words wider than a line are broken between
+// grapheme clusters. The spaces at line breaks are dropped.
+func wrapText(s string, width int) []span {
+	if width <= 0 || runewidth.StringWidth(s) <= width {
+		return []span{{0, len(s)}}
+	}
+
+	var lines []span
+	var w int
+	start := 0
+
+	// brk is the start of the last word of the line and end the end of the
+	// text before the spaces in front of it. spaces is the start of the
+	// current run of spaces, or -1.
+	brk, end, spaces := -1, -1, -1
+	for _, g := range graphemes(s, 0) {
+		space := g.text == " "
+		if space && w == 0 {
+			start = g.pos + len(g.text)
+			continue
+		}
+		if space && spaces < 0 {
+			spaces = g.pos
+		}
+		if !space && spaces >= 0 {
+			brk, end, spaces = g.pos, spaces, -1
+		}
+
+		gw := runewidth.StringWidth(g.text)
+		if !space && w > 0 && w+gw > width {
+			if brk > start {
+				lines = append(lines, span{start, end})
+				start = brk
+				w = runewidth.StringWidth(s[brk:g.pos])
+			} else {
+				lines = append(lines, span{start, g.pos})
+				start, w = g.pos, 0
+			}
+		}
+		w += gw
+	}
+
+	last := len(s)
+	if spaces >= 0 {
+		last = spaces
+	}
+	if start < last || len(lines) == 0 {
+		lines = append(lines, span{start, max(start, last)})
+	}
+	return lines
+}
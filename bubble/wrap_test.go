@@ -0,0 +1,38 @@
+package bubble
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		width int
+		want  []string
+	}{
+		{"it keeps text that fits", "hello world", 20, []string{"hello world"}},
+		{"it keeps text without a width", "hello world", 0, []string{"hello world"}},
+		{"it keeps empty text", "", 5, []string{""}},
+		{"it breaks between words", "hello world", 5, []string{"hello", "world"}},
+		{"it drops the spaces at breaks", "one  two", 4, []string{"one", "two"}},
+		{"it fills lines with words", "a b c d e", 3, []string{"a b", "c d", "e"}},
+		{"it breaks long words", "abcdefgh", 3, []string{"abc", "def", "gh"}},
+		{"it breaks wide characters", "東京大阪", 4, []string{"東京", "大阪"}},
+		{"it doesn't split wide characters", "a東京", 2, []string{"a", "東", "京"}},
+		{"it keeps grapheme clusters", "👨‍👩‍👧👨‍👩‍👧", 2, []string{"👨‍👩‍👧", "👨‍👩‍👧"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, s := range wrapText(tt.value, tt.width) {
+				got = append(got, tt.value[s.start:s.end])
+			}
+			if !reflect.DeepEqual(tt.want, got) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the user configuration read from the config file, for example
+//
+//	theme: light
+//	keys:
+//	  delete: ["ctrl+x"]
+//	  switch_cursor_mode: ["ctrl+o", "m"]
+type Config struct {
+	// Theme is the name of the color theme.
+	Theme string `yaml:"theme"`
+
+	// Keys maps the names of key actions to the keys that trigger them.
+	Keys map[string][]string `yaml:"keys"`
+}
+
+// Path returns the default path of the config file,
+// $XDG_CONFIG_HOME/wikitable/config.yaml.
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "wikitable", "config.yaml"), nil
+}
+
+// Load reads the config file at path. A missing file is an empty config.
+func Load(path string) (Config, error) {
+	var cfg Config
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
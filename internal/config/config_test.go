@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("it loads theme and keys", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		err := os.WriteFile(path, []byte("theme: light\nkeys:\n  delete: [\"ctrl+x\"]\n  switch_cursor_mode: [\"ctrl+o\", \"m\"]\n"), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := Load(path)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := Config{
+			Theme: "light",
+			Keys: map[string][]string{
+				"delete":             {"ctrl+x"},
+				"switch_cursor_mode": {"ctrl+o", "m"},
+			},
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("it ignores a missing file", func(t *testing.T) {
+		got, err := Load(filepath.Join(t.TempDir(), "config.yaml"))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !reflect.DeepEqual(Config{}, got) {
+			t.Errorf("expected empty config, got %v", got)
+		}
+	})
+
+	t.Run("it returns error on invalid yaml", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte("keys: [\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Load(path); err == nil {
+			t.Errorf("expected error, got nil")
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package model
+
+import "github.com/atye/wikitable/bubble"
+
+// autoAlign aligns numeric columns on their decimal separator and other
+// columns left.
+const autoAlign bubble.Alignment = -1
+
+// alignments is the order in which the alignment of a column is cycled.
+var alignments = []bubble.Alignment{bubble.AlignLeft, bubble.AlignCenter, bubble.AlignRight, bubble.AlignDecimal}
+
+// columnAlign returns the alignment of a column of the table.
+func (t *table) columnAlign(col int) bubble.Alignment {
+	if a := t.aligns[col]; a != autoAlign {
+		return a
+	}
+	if t.isNumeric(col) {
+		return bubble.AlignDecimal
+	}
+	return bubble.AlignLeft
+}
+
+// cycleAlign sets the alignment of a column to the next one in alignments.
+func (t *table) cycleAlign(col int) {
+	if col >= len(t.aligns) {
+		return
+	}
+	t.edit()
+	current := t.columnAlign(col)
+	for i, a := range alignments {
+		if a == current {
+			t.aligns[col] = alignments[(i+1)%len(alignments)]
+			break
+		}
+	}
+	t.set()
+}
+
+// isNumeric reports whether at least 90% of the non-empty cells of a column
+// are numbers.
+func (t *table) isNumeric(col int) bool {
+	return t.parsedColumn(col).numeric
+}
+
+func newAligns(n int) []bubble.Alignment {
+	aligns := make([]bubble.Alignment, n)
+	for i := range aligns {
+		aligns[i] = autoAlign
+	}
+	return aligns
+}
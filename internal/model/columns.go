@@ -0,0 +1,176 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// widenColumn changes the width of the selected column by n, which narrows
+// it if n is negative. The columns of a transposed table are rows of data, so
+// they can't be resized, hidden or moved.
+func (t *table) widenColumn(n int) {
+	if t.model.CursorMode() != "column" || len(t.cols) == 0 || t.transposed {
+		return
+	}
+	t.edit()
+	col := t.model.ColumnCursor()
+	t.resizeColumn(t.column(col), t.model.Columns()[col].Width+n)
+}
+
+// hideColumn hides the selected column. The last column shown is not hidden.
+func (t *table) hideColumn() {
+	if t.model.CursorMode() != "column" || len(t.cols) <= 1 || t.transposed {
+		return
+	}
+	t.edit()
+	col := t.model.ColumnCursor()
+	t.hidden[t.column(col)] = true
+	t.set()
+	t.model.SetCursor(min(col, len(t.cols)-1))
+}
+
+// showColumn shows the hidden column of data at col.
+func (t *table) showColumn(col int) {
+	t.edit()
+	t.hidden[col] = false
+	t.set()
+}
+
+// hiddenColumns returns the index in data of each hidden column.
+func (t *table) hiddenColumns() []int {
+	var cols []int
+	for i, hidden := range t.hidden {
+		if hidden {
+			cols = append(cols, i)
+		}
+	}
+	return cols
+}
+
+// moveColumn moves the selected column n columns to the right among the
+// columns shown, or to the left if n is negative. In a right-to-left table
+// the columns are mirrored, so right is towards the first column.
+func (t *table) moveColumn(n int) {
+	if t.model.CursorMode() != "column" || len(t.cols) == 0 || t.transposed {
+		return
+	}
+	if t.model.RTL() {
+		n = -n
+	}
+	col := t.model.ColumnCursor()
+	to := clamp(col+n, 0, len(t.cols)-1)
+	if to == col {
+		return
+	}
+	t.edit()
+
+	from, dest := t.column(col), t.column(to)
+	data := make([][]string, len(t.data))
+	for i, row := range t.data {
+		data[i] = move(row, from, dest)
+	}
+	t.data = data
+	t.widths = move(t.widths, from, dest)
+	t.aligns = move(t.aligns, from, dest)
+	t.rules = move(t.rules, from, dest)
+	t.hidden = move(t.hidden, from, dest)
+
+	// Sort keys follow their columns.
+	moved := move(indexes(len(t.data[0])), from, dest)
+	for i, k := range t.sortKeys {
+		for j, c := range moved {
+			if c == k.col {
+				t.sortKeys[i].col = j
+				break
+			}
+		}
+	}
+
+	t.set()
+	t.model.SetCursor(to)
+}
+
+// move returns a copy of s with the element at from moved to index to.
+func move[T any](s []T, from, to int) []T {
+	moved := make([]T, 0, len(s))
+	moved = append(moved, s[:from]...)
+	moved = append(moved, s[from+1:]...)
+	moved = append(moved[:to], append([]T{s[from]}, moved[to:]...)...)
+	return moved
+}
+
+func indexes(n int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i
+	}
+	return s
+}
+
+// columnsPanel lists the hidden columns of the current table to show them
+// again.
+type columnsPanel struct {
+	selected int
+}
+
+// openColumns opens the hidden columns panel. It reports false if no column
+// is hidden.
+func (m *Model) openColumns() bool {
+	if len(m.tables[m.index].hiddenColumns()) == 0 {
+		return false
+	}
+	m.columns = columnsPanel{}
+	return true
+}
+
+func (m *Model) updateColumns(msg tea.Msg) tea.Cmd {
+	t := m.tables[m.index]
+	hidden := t.hiddenColumns()
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.ForceQuit):
+			return tea.Quit
+		case key.Matches(msg, m.keys.CloseColumns):
+			m.mode = "table"
+		case key.Matches(msg, m.keys.NextHidden):
+			m.columns.selected = clamp(m.columns.selected+1, 0, len(hidden)-1)
+		case key.Matches(msg, m.keys.PrevHidden):
+			m.columns.selected = clamp(m.columns.selected-1, 0, len(hidden)-1)
+		case key.Matches(msg, m.keys.ShowColumn):
+			t.showColumn(hidden[m.columns.selected])
+			if len(hidden) == 1 {
+				m.mode = "table"
+				return nil
+			}
+			m.columns.selected = clamp(m.columns.selected, 0, len(hidden)-2)
+		}
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
+	}
+	return nil
+}
+
+func (m *Model) viewColumns() string {
+	t := m.tables[m.index]
+
+	var b strings.Builder
+	b.WriteString(m.theme.Title.Render("Hidden columns"))
+	b.WriteString("\n\n")
+	for i, col := range t.hiddenColumns() {
+		if i == m.columns.selected {
+			b.WriteString(m.theme.Focused.Render("> " + t.data[0][col]))
+		} else {
+			b.WriteString("  " + t.data[0][col])
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.shortHelpView())
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.Popup.Render(b.String()))
+}
@@ -0,0 +1,132 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	cellDetail = "cell"
+	rowDetail  = "row"
+)
+
+// detail is a popup that shows the full text of the selected cell, or the
+// selected row as a record card with one header and value per line.
+type detail struct {
+	view     string
+	title    string
+	viewport viewport.Model
+}
+
+// openDetail opens the detail popup for the selected cell or row of the
+// current table. It reports false if there is nothing to show.
+func (m *Model) openDetail(view string) bool {
+	t := m.tables[m.index]
+	cols := t.model.Columns()
+	if len(t.model.Rows()) == 0 || len(cols) == 0 {
+		return false
+	}
+
+	width, height := m.detailSize()
+	m.detail = detail{
+		view:     view,
+		viewport: viewport.New(width, height),
+	}
+	m.setDetailContent()
+	return true
+}
+
+func (m *Model) setDetailContent() {
+	t := m.tables[m.index]
+	cols := t.model.Columns()
+	row := t.model.SelectedRow()
+	width := m.detail.viewport.Width
+	wrap := lipgloss.NewStyle().Width(width)
+
+	var content string
+	switch m.detail.view {
+	case cellDetail:
+		col := t.model.ColumnCursor()
+		m.detail.title = cols[col].Title
+		content = wrap.Render(row[col])
+	case rowDetail:
+		m.detail.title = t.rowTitle(t.model.RowCursor())
+		lines := make([]string, 0, len(cols))
+		for i, col := range cols {
+			lines = append(lines, wrap.Render(m.theme.Title.Render(col.Title+":")+" "+row[i]))
+		}
+		content = strings.Join(lines, "\n")
+	}
+
+	m.detail.viewport.SetContent(content)
+	m.detail.viewport.GotoTop()
+}
+
+func (m *Model) updateDetail(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.ForceQuit):
+			return tea.Quit
+		case key.Matches(msg, m.keys.CloseDetail):
+			m.mode = "table"
+			return nil
+		case key.Matches(msg, m.keys.ToggleDetail):
+			if m.detail.view == cellDetail {
+				m.detail.view = rowDetail
+			} else {
+				m.detail.view = cellDetail
+			}
+			m.setDetailContent()
+			return nil
+		}
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
+		m.detail.viewport.Width, m.detail.viewport.Height = m.detailSize()
+		m.setDetailContent()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.detail.viewport, cmd = m.detail.viewport.Update(msg)
+	return cmd
+}
+
+func (m *Model) viewDetail() string {
+	var b strings.Builder
+	b.WriteString(m.theme.Title.Render(m.detail.title))
+	b.WriteString("\n\n")
+	b.WriteString(m.detail.viewport.View())
+	b.WriteString("\n\n")
+	b.WriteString(m.shortHelpView())
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.Popup.Render(b.String()))
+}
+
+// detailSize returns the size of the popup's scrollable area, leaving room
+// for the border, title and footer.
+func (m *Model) detailSize() (int, int) {
+	width, height := m.width*3/4, m.height*3/4-6
+	if width < 20 {
+		width = 20
+	}
+	if height < 3 {
+		height = 3
+	}
+	return width, height
+}
+
+// rowTitle returns the title of the detail of a row of the model, which names
+// the row by the number it was read with, like the row numbers. A row of a
+// transposed table is a column, named by its title.
+func (t *table) rowTitle(row int) string {
+	if t.transposed {
+		return "Column " + t.data[0][t.cols[row+1]]
+	}
+	return "Row " + strconv.Itoa(t.ids[t.rows[row]])
+}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/atye/wikitable/bubble"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// minEditWidth is the width a column is widened to at least while one of its
+// cells is edited.
+const minEditWidth = 20
+
+// editor edits the value of a cell or the title of a column of the current
+// table in place. row and col are the cell edited in the model, where row -1
+// is the header.
+type editor struct {
+	input textinput.Model
+	row   int
+	col   int
+}
+
+func newEditInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = ""
+	return input
+}
+
+// openEditor starts editing the selected cell, or the title of the selected
+// column if header is set.
+func (m *Model) openEditor(header bool) tea.Cmd {
+	t := m.tables[m.index]
+
+	row, col := t.model.RowCursor(), t.model.ColumnCursor()
+	if header {
+		row = -1
+	}
+	r, c := t.dataCell(row, col)
+
+	m.editor.row, m.editor.col = row, col
+	m.editor.input.SetValue(t.data[r][c])
+	m.editor.input.CursorEnd()
+
+	// Widen the column in the model only, until the edit is done.
+	columns := make([]bubble.Column, len(t.model.Columns()))
+	copy(columns, t.model.Columns())
+	columns[col].Width = max(columns[col].Width, minEditWidth)
+	t.model.SetColumns(columns)
+	m.editor.input.Width = columns[col].Width - 1
+
+	cmd := m.editor.input.Focus()
+	t.model.SetEditor(row, col, m.editor.input.View())
+	return cmd
+}
+
+func (m *Model) updateEditor(msg tea.Msg) tea.Cmd {
+	t := m.tables[m.index]
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.ForceQuit):
+			return tea.Quit
+		case key.Matches(msg, m.keys.Confirm):
+			m.closeEditor()
+			r, c := t.dataCell(m.editor.row, m.editor.col)
+			t.setCell(r, c, m.editor.input.Value())
+			return nil
+		case key.Matches(msg, m.keys.Cancel):
+			m.closeEditor()
+			return nil
+		}
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.editor.input, cmd = m.editor.input.Update(msg)
+	t.model.SetEditor(m.editor.row, m.editor.col, m.editor.input.View())
+	return cmd
+}
+
+// closeEditor stops editing and lays out the table again, which sizes the
+// column widened for the edit back to its content.
+func (m *Model) closeEditor() {
+	t := m.tables[m.index]
+	m.editor.input.Blur()
+	t.model.ClearEditor()
+	t.set()
+	m.mode = "table"
+}
+
+// viewEditor renders the footer shown while a cell is edited, which names the
+// cell.
+func (m *Model) viewEditor() string {
+	t := m.tables[m.index]
+	r, c := t.dataCell(m.editor.row, m.editor.col)
+	if r == 0 {
+		return m.theme.Focused.Render(fmt.Sprintf("editing the title of %s", t.data[0][c]))
+	}
+	return m.theme.Focused.Render(fmt.Sprintf("editing %s of row %d", t.data[0][c], t.ids[r]))
+}
+
+// setCell sets the value of a cell of data, where row 0 holds the titles of
+// the columns. The edited row stays selected if it is still shown.
+func (t *table) setCell(row, col int, value string) {
+	if t.data[row][col] == value {
+		return
+	}
+	t.edit()
+
+	data := append([][]string(nil), t.data...)
+	data[row] = append([]string(nil), data[row]...)
+	data[row][col] = value
+	t.data = data
+	t.set()
+	if row > 0 {
+		t.selectRow(row)
+	}
+}
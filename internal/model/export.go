@@ -0,0 +1,112 @@
+package model
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// export is the prompt for the file the selected cells are written to. An
+// existing file is only replaced once the export to it is confirmed again.
+type export struct {
+	input textinput.Model
+	err   error
+
+	// overwrite is the path of the existing file to replace on the next
+	// confirmation.
+	overwrite string
+}
+
+func newExportInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = "export to: "
+	input.Placeholder = "selection.csv"
+	return input
+}
+
+func (m *Model) openExport() tea.Cmd {
+	m.export.err = nil
+	m.export.overwrite = ""
+	m.export.input.CursorEnd()
+	return m.export.input.Focus()
+}
+
+func (m *Model) updateExport(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.ForceQuit):
+			return tea.Quit
+		case key.Matches(msg, m.keys.Confirm):
+			path := strings.TrimSpace(m.export.input.Value())
+			if path == "" {
+				path = m.export.input.Placeholder
+			}
+			err := m.tables[m.index].exportSelection(path, path == m.export.overwrite)
+			if errors.Is(err, os.ErrExist) {
+				m.export.err = fmt.Errorf("%s exists, press enter again to replace it", path)
+				m.export.overwrite = path
+				return nil
+			}
+			if err != nil {
+				m.export.err = err
+				return nil
+			}
+			m.export.input.Blur()
+			m.closeVisual()
+			return nil
+		case key.Matches(msg, m.keys.Cancel):
+			m.export.input.Blur()
+			m.mode = "visual"
+			return nil
+		}
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.export.input, cmd = m.export.input.Update(msg)
+	return cmd
+}
+
+func (m *Model) viewExport() string {
+	view := m.export.input.View()
+	if m.export.err != nil {
+		view += "  " + m.theme.Error.Render(m.export.err.Error())
+	}
+	return view
+}
+
+// exportSelection writes the selected cells to the file at path, with the
+// titles of their columns on the first line. Files ending in .tsv get
+// tab-separated values, and other files comma-separated values. An existing
+// file is only replaced if overwrite is set, and otherwise the error is
+// os.ErrExist.
+func (t *table) exportSelection(path string, overwrite bool) error {
+	flag := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if overwrite {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flag, 0o644)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(f)
+	if strings.EqualFold(filepath.Ext(path), ".tsv") {
+		w.Comma = '\t'
+	}
+	if err := w.WriteAll(t.selectionRecords()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,564 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A filter expression selects the rows of a table to show, for example
+//
+//	Population > 1e6 && Continent ~ "Asia"
+//
+// Columns are referred to by header title, either as a bare word or quoted
+// with backticks. Comparisons are numeric when both sides are numbers or
+// dates, and case-insensitive otherwise. ~ tests if a column contains a
+// string or matches a /regex/, and empty(Column) tests for empty cells.
+// Expressions are combined with && (and), || (or), ! (not) and parentheses.
+type filter func(row []string) bool
+
+func newFilterInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = "filter: "
+	return input
+}
+
+func (m *Model) openFilter() tea.Cmd {
+	m.filterInput.SetValue(m.tables[m.index].filter)
+	m.filterInput.CursorEnd()
+	m.filterErr = nil
+	return m.filterInput.Focus()
+}
+
+func (m *Model) updateFilter(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.ForceQuit):
+			return tea.Quit
+		case key.Matches(msg, m.keys.Confirm):
+			if err := m.tables[m.index].setFilter(m.filterInput.Value()); err != nil {
+				m.filterErr = err
+				return nil
+			}
+			m.filterInput.Blur()
+			m.mode = "table"
+			return nil
+		case key.Matches(msg, m.keys.Cancel):
+			m.filterInput.Blur()
+			m.mode = "table"
+			return nil
+		}
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	return cmd
+}
+
+func (m *Model) viewFilter() string {
+	view := m.filterInput.View()
+	if m.filterErr != nil {
+		view += "  " + m.theme.Error.Render(m.filterErr.Error())
+	}
+	return view
+}
+
+// compiledFilter is the filter of a table compiled for the titles of its
+// columns, or the error compiling it.
+type compiledFilter struct {
+	expr    string
+	headers []string
+	match   filter
+	err     error
+}
+
+// setFilter filters the rows of the table with expr. An empty expression
+// clears the filter.
+func (t *table) setFilter(expr string) error {
+	expr = strings.TrimSpace(expr)
+	compiled := compiledFilter{expr: expr, headers: t.data[0]}
+	if expr != "" {
+		match, err := compileFilter(expr, t.data[0])
+		if err != nil {
+			return err
+		}
+		compiled.match = match
+	}
+
+	if expr != t.filter {
+		t.edit()
+	}
+	t.filter = expr
+	t.compiled = compiled
+	t.set()
+	t.model.SelectCell(0, t.model.ColumnCursor())
+	return nil
+}
+
+// filterRows returns the data rows that match the table's filter. The filter
+// is compiled again only when the titles of the columns change. A filter that
+// no longer compiles, e.g. because its column was renamed or removed, is
+// turned off, and filterError reports why.
+func (t *table) filterRows(rows []int) []int {
+	if t.filter == "" {
+		return rows
+	}
+
+	if t.compiled.expr != t.filter || !equalStrings(t.compiled.headers, t.data[0]) {
+		match, err := compileFilter(t.filter, t.data[0])
+		t.compiled = compiledFilter{expr: t.filter, headers: t.data[0], match: match, err: err}
+	}
+	if t.compiled.err != nil {
+		return rows
+	}
+
+	filtered := rows[:0]
+	for _, r := range rows {
+		if t.compiled.match(t.data[r]) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// filterError returns the error compiling the filter of the table for the
+// current titles of its columns, if any.
+func (t *table) filterError() error {
+	if t.filter == "" {
+		return nil
+	}
+	return t.compiled.err
+}
+
+// equalStrings reports whether a and b hold the same strings.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// compileFilter compiles a filter expression for a table with the given
+// headers.
+func compileFilter(expr string, headers []string) (filter, error) {
+	return compileColumnFilter(expr, headers, -1)
+}
+
+// compileColumnFilter compiles a filter expression in which a comparison
+// starting with an operator, as in > 100, compares column col. The column is
+// found by its index, so the comparison holds when the column has the title
+// of another column or is renamed.
+func compileColumnFilter(expr string, headers []string, col int) (filter, error) {
+	tokens, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens, headers: headers, self: col}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != endToken {
+		return nil, fmt.Errorf("unexpected %s", tok)
+	}
+	return f, nil
+}
+
+type tokenKind int
+
+const (
+	endToken tokenKind = iota
+	identToken
+	stringToken
+	regexToken
+	numberToken
+	opToken
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case endToken:
+		return "end of filter"
+	case regexToken:
+		return "/" + t.value + "/"
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+var filterOps = []string{"&&", "||", "==", "!=", ">=", "<=", "!~", "=", ">", "<", "~", "!", "(", ")"}
+
+func lexFilter(expr string) ([]token, error) {
+	var tokens []token
+	s := expr
+	for {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		if s == "" {
+			return append(tokens, token{kind: endToken}), nil
+		}
+
+		switch c := s[0]; {
+		case c == '"':
+			value, rest, err := lexQuoted(s, '"')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: stringToken, value: value})
+			s = rest
+		case c == '`':
+			value, rest, err := lexQuoted(s, '`')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: identToken, value: value})
+			s = rest
+		case c == '/':
+			value, rest, err := lexQuoted(s, '/')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: regexToken, value: value})
+			s = rest
+		case isNumberStart(s):
+			end := 1
+			for end < len(s) && (isDigit(s[end]) || strings.IndexByte(".,eE", s[end]) >= 0 ||
+				(strings.IndexByte("+-", s[end]) >= 0 && strings.IndexByte("eE", s[end-1]) >= 0)) {
+				end++
+			}
+			tokens = append(tokens, token{kind: numberToken, value: s[:end]})
+			s = s[end:]
+		default:
+			if op := lexOp(s); op != "" {
+				tokens = append(tokens, token{kind: opToken, value: op})
+				s = s[len(op):]
+				continue
+			}
+
+			end := strings.IndexFunc(s, func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+			})
+			if end < 0 {
+				end = len(s)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("unexpected character %q", []rune(s)[0])
+			}
+			tokens = append(tokens, token{kind: identToken, value: s[:end]})
+			s = s[end:]
+		}
+	}
+}
+
+// lexQuoted reads a string quoted with q, in which a backslash escapes the
+// next character, and returns it with the rest of s.
+func lexQuoted(s string, q byte) (string, string, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				if s[i] != q && q == '/' {
+					b.WriteByte('\\')
+				}
+				b.WriteByte(s[i])
+			}
+		case q:
+			return b.String(), s[i+1:], nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", "", fmt.Errorf("missing closing %c", q)
+}
+
+func lexOp(s string) string {
+	for _, op := range filterOps {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func isNumberStart(s string) bool {
+	switch {
+	case isDigit(s[0]):
+		return true
+	case len(s) > 1 && (s[0] == '-' || s[0] == '.'):
+		return isDigit(s[1])
+	}
+	return false
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+type filterParser struct {
+	tokens  []token
+	pos     int
+	headers []string
+
+	// self is the column compared by a comparison starting with an
+	// operator, or -1 if the left side is always given.
+	self int
+}
+
+func (p *filterParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != endToken {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is one of the given operators or
+// keywords.
+func (p *filterParser) accept(values ...string) bool {
+	tok := p.peek()
+	if tok.kind != opToken && tok.kind != identToken {
+		return false
+	}
+	for _, v := range values {
+		if tok.value == v {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||", "or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(row []string) bool { return l(row) || right(row) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filter, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&", "and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(row []string) bool { return l(row) && right(row) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (filter, error) {
+	if p.accept("!", "not") {
+		f, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(row []string) bool { return !f(row) }, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *filterParser) parsePrimary() (filter, error) {
+	if p.accept("(") {
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("expected ) but got %s", p.peek())
+		}
+		return f, nil
+	}
+
+	if tok := p.peek(); tok.kind == identToken && tok.value == "empty" && p.tokens[p.pos+1].value == "(" {
+		p.pos += 2
+		col, err := p.parseColumn()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("expected ) but got %s", p.peek())
+		}
+		return func(row []string) bool { return strings.TrimSpace(row[col]) == "" }, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseColumn() (int, error) {
+	tok := p.next()
+	if tok.kind != identToken {
+		return 0, fmt.Errorf("expected column but got %s", tok)
+	}
+	return p.column(tok.value)
+}
+
+// column finds a column by its title, preferring an exact match over a
+// case-insensitive one.
+func (p *filterParser) column(name string) (int, error) {
+	for i, h := range p.headers {
+		if h == name {
+			return i, nil
+		}
+	}
+	for i, h := range p.headers {
+		if strings.EqualFold(h, name) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown column %q", name)
+}
+
+// operand returns the value of a column or literal in a row.
+type operand func(row []string) string
+
+func (p *filterParser) parseOperand() (operand, error) {
+	tok := p.next()
+	switch tok.kind {
+	case identToken:
+		col, err := p.column(tok.value)
+		if err != nil {
+			return nil, err
+		}
+		return func(row []string) string { return row[col] }, nil
+	case stringToken:
+		return func([]string) string { return tok.value }, nil
+	case numberToken:
+		if _, ok := parseNumber(tok.value); !ok {
+			return nil, fmt.Errorf("invalid number %s", tok)
+		}
+		return func([]string) string { return tok.value }, nil
+	default:
+		return nil, fmt.Errorf("expected column or value but got %s", tok)
+	}
+}
+
+func (p *filterParser) parseComparison() (filter, error) {
+	var left operand
+	if tok := p.peek(); p.self >= 0 && tok.kind == opToken && comparisonOps[tok.value] {
+		col := p.self
+		left = func(row []string) string { return row[col] }
+	} else {
+		var err error
+		if left, err = p.parseOperand(); err != nil {
+			return nil, err
+		}
+	}
+
+	op := p.next()
+	if op.kind != opToken {
+		return nil, fmt.Errorf("expected comparison but got %s", op)
+	}
+
+	switch op.value {
+	case "~", "!~":
+		match, err := p.parseMatch()
+		if err != nil {
+			return nil, err
+		}
+		negate := op.value == "!~"
+		return func(row []string) bool { return match(left(row)) != negate }, nil
+	case "==", "=", "!=", "<", "<=", ">", ">=":
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		test := comparisons[op.value]
+		return func(row []string) bool { return test(compareFilterValues(left(row), right(row))) }, nil
+	default:
+		return nil, fmt.Errorf("expected comparison but got %s", op)
+	}
+}
+
+// parseMatch parses the right side of ~, a /regex/ or a smart-case string to
+// search for.
+func (p *filterParser) parseMatch() (func(string) bool, error) {
+	tok := p.next()
+	switch tok.kind {
+	case regexToken:
+		re, err := regexp.Compile(tok.value)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	case stringToken, numberToken:
+		re, err := compileQuery(tok.value, false)
+		if err != nil || re == nil {
+			return func(string) bool { return true }, err
+		}
+		return re.MatchString, nil
+	default:
+		return nil, fmt.Errorf("expected string or /regex/ but got %s", tok)
+	}
+}
+
+// comparisonOps holds the operators that can follow the left side of a
+// comparison.
+var comparisonOps = map[string]bool{
+	"==": true, "=": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true, "~": true, "!~": true,
+}
+
+var comparisons = map[string]func(int) bool{
+	"==": func(n int) bool { return n == 0 },
+	"=":  func(n int) bool { return n == 0 },
+	"!=": func(n int) bool { return n != 0 },
+	"<":  func(n int) bool { return n < 0 },
+	"<=": func(n int) bool { return n <= 0 },
+	">":  func(n int) bool { return n > 0 },
+	">=": func(n int) bool { return n >= 0 },
+}
+
+// compareFilterValues compares two values by number or date if both are of
+// the same kind, and otherwise as case-insensitive text.
+func compareFilterValues(a, b string) int {
+	va, vb := parseSortValue(a), parseSortValue(b)
+	if va.kind == vb.kind && (va.kind == numberKind || va.kind == dateKind) {
+		switch {
+		case va.number < vb.number:
+			return -1
+		case va.number > vb.number:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(strings.ToLower(strings.TrimSpace(a)), strings.ToLower(strings.TrimSpace(b)))
+}
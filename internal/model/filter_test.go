@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	headers := []string{"Country", "Continent", "Population", "Area (km2)", "Notes"}
+	rows := [][]string{
+		{"Japan", "Asia", "125,700,000", "377,975", ""},
+		{"Nepal", "Asia", "30,550,000", "147,516", "landlocked"},
+		{"Iceland", "Europe", "387,758", "103,000", ""},
+	}
+
+	tests := []struct {
+		expr string
+		want []bool
+	}{
+		{`Population > 1e6 && Continent ~ "Asia"`, []bool{true, true, false}},
+		{`population >= 30,550,000`, []bool{true, true, false}},
+		{`Continent == "europe"`, []bool{false, false, true}},
+		{`Continent != "Europe" and not Country ~ "jap"`, []bool{false, true, false}},
+		{"`Area (km2)` < 150000", []bool{false, true, true}},
+		{`Country ~ /^[JI]/`, []bool{true, false, true}},
+		{`Country !~ /^[JI]/`, []bool{false, true, false}},
+		{`empty(Notes) || Country = "Nepal"`, []bool{true, true, true}},
+		{`!(empty(Notes))`, []bool{false, true, false}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			f, err := compileFilter(tc.expr, headers)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			for i, row := range rows {
+				if got := f(row); got != tc.want[i] {
+					t.Errorf("expected %v for %v, got %v", tc.want[i], row, got)
+				}
+			}
+		})
+	}
+
+	errors := []string{
+		`Population >`,
+		`Capital == "Tokyo"`,
+		`Country ~ /[/`,
+		`(Country ~ "a"`,
+		`Country "a"`,
+		`Country == "a`,
+	}
+
+	for _, expr := range errors {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := compileFilter(expr, headers); err == nil {
+				t.Errorf("expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/atye/wikitable/bubble"
+)
+
+// minFitWidth is the width auto-fit shrinks columns to at most, before the
+// table scrolls instead.
+const minFitWidth = 6
+
+// columnStats holds statistics of the display widths of the cells of a
+// column.
+type columnStats struct {
+	max     int
+	median  int
+	p90     int
+	numeric bool
+}
+
+// fitColumns shrinks the columns that are sized to their content so that the
+// table fits the width of its model. widths holds the width of each column
+// shown sized to its content. Columns the user resized keep their width.
+func (t *table) fitColumns(widths []int) {
+	if t.model.Width() <= 0 {
+		return
+	}
+	available := t.model.Width() - t.model.FrameWidth(len(widths))
+
+	var cols []int
+	var stats []columnStats
+	for i, w := range widths {
+		if t.widths[t.column(i)] > 0 {
+			available -= w
+			continue
+		}
+		s := t.columnStats(t.column(i))
+		s.max = w
+		cols = append(cols, i)
+		stats = append(stats, s)
+	}
+
+	for i, w := range fitWidths(stats, available) {
+		widths[cols[i]] = w
+	}
+}
+
+// columnStats returns the statistics of the widths of the rows of a column.
+func (t *table) columnStats(col int) columnStats {
+	cells := make([]string, 0, len(t.data)-1)
+	for _, row := range t.data[1:] {
+		cells = append(cells, row[col])
+	}
+	s := widthStats(cells)
+	s.numeric = t.isNumeric(col)
+	return s
+}
+
+// widthStats returns the median and 90th percentile of the widths of cells.
+func widthStats(cells []string) columnStats {
+	widths := make([]int, len(cells))
+	for i, cell := range cells {
+		widths[i] = bubble.Width(cell)
+	}
+	sort.Ints(widths)
+
+	var s columnStats
+	if len(widths) > 0 {
+		s.median = widths[(len(widths)-1)/2]
+		s.p90 = widths[(len(widths)-1)*9/10]
+	}
+	return s
+}
+
+// fitWidths returns the widths of columns that fit in width, or as close to
+// it as the columns can be shrunk. Columns are shrunk in stages until they
+// fit: first text columns down to the width of 90% of their cells, then text
+// columns down to their median, then all columns down to minFitWidth. Within
+// the stage that fits, the width left is shared by the columns in proportion
+// to how much they were shrunk.
+func fitWidths(stats []columnStats, width int) []int {
+	widths := make([]int, len(stats))
+	for i, s := range stats {
+		widths[i] = s.max
+	}
+
+	stages := []func(columnStats) int{
+		func(s columnStats) int {
+			if s.numeric {
+				return s.max
+			}
+			return max(s.p90, minFitWidth)
+		},
+		func(s columnStats) int {
+			if s.numeric {
+				return s.max
+			}
+			return max(s.median, minFitWidth)
+		},
+		func(columnStats) int {
+			return minFitWidth
+		},
+	}
+
+	for _, stage := range stages {
+		if sum(widths) <= width {
+			return widths
+		}
+		shrunk := make([]int, len(widths))
+		for i, s := range stats {
+			shrunk[i] = min(widths[i], stage(s))
+		}
+		if sum(shrunk) <= width {
+			return spread(widths, shrunk, width)
+		}
+		widths = shrunk
+	}
+	return widths
+}
+
+// spread grows the widths in to towards those in from until they add up to
+// width, in proportion to the difference between them. The widths in from
+// must add up to more than width, and those in to to at most width.
+func spread(from, to []int, width int) []int {
+	widths := make([]int, len(to))
+	copy(widths, to)
+
+	extra := width - sum(to)
+	slack := sum(from) - sum(to)
+	var given int
+	for i := range widths {
+		n := (from[i] - to[i]) * extra / slack
+		widths[i] += n
+		given += n
+	}
+	// Give what is left of the rounding to the columns in order.
+	for i := 0; given < extra; i = (i + 1) % len(widths) {
+		if widths[i] < from[i] {
+			widths[i]++
+			given++
+		}
+	}
+	return widths
+}
+
+func sum(values []int) int {
+	var s int
+	for _, v := range values {
+		s += v
+	}
+	return s
+}
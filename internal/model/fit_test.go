@@ -0,0 +1,34 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFitWidths(t *testing.T) {
+	stats := []columnStats{
+		{max: 40, median: 8, p90: 12},
+		{max: 10, median: 9, p90: 10, numeric: true},
+	}
+
+	tests := []struct {
+		name  string
+		width int
+		want  []int
+	}{
+		{"it keeps columns that fit", 50, []int{40, 10}},
+		{"it shrinks the long tail of text columns", 30, []int{20, 10}},
+		{"it shrinks text columns to their median", 18, []int{8, 10}},
+		{"it shrinks numeric columns last", 14, []int{7, 7}},
+		{"it stops at the minimum width", 5, []int{minFitWidth, minFitWidth}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := fitWidths(stats, tc.width)
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,394 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// A format rule colors the cells of a column, for example
+//
+//	red if > 1e6
+//	black on yellow if ~ /^Japan/
+//	green if Continent == "Asia"
+//	heatmap
+//	heatmap white red
+//
+// A rule with a condition colors the cells of the rows that match a filter
+// expression, where a comparison starting with an operator compares the
+// column itself, whatever its title. The first matching rule of a column is used. A heatmap
+// colors the background of numbers on a gradient from the smallest to the
+// largest number shown.
+type formatRule struct {
+	expr  string
+	style lipgloss.Style
+
+	heatmap   bool
+	low, high rgb
+}
+
+// rgb is a color that can be interpolated.
+type rgb struct {
+	r, g, b int
+}
+
+var (
+	// colorNames holds the colors that can be used by name in format rules.
+	colorNames = map[string]string{
+		"black":   "#000000",
+		"white":   "#ffffff",
+		"gray":    "#808080",
+		"red":     "#d7191c",
+		"orange":  "#fd8d3c",
+		"yellow":  "#ffd700",
+		"green":   "#1a9641",
+		"cyan":    "#00b7c3",
+		"blue":    "#2c7bb6",
+		"purple":  "#7b3294",
+		"magenta": "#d01c8b",
+		"pink":    "#f4a6c6",
+	}
+
+	defaultHeatmap = [2]string{"blue", "red"}
+)
+
+func newFormatInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "red if > 100, heatmap, or empty to clear"
+	return input
+}
+
+func (m *Model) openFormat() tea.Cmd {
+	t := m.tables[m.index]
+	col := t.column(t.model.ColumnCursor())
+
+	prompt := fmt.Sprintf("format %s: ", t.data[0][col])
+	if n := len(t.rules[col]); n > 0 {
+		prompt = fmt.Sprintf("format %s (%d rules): ", t.data[0][col], n)
+	}
+	m.formatInput.Prompt = prompt
+	m.formatInput.SetValue("")
+	m.formatErr = nil
+	return m.formatInput.Focus()
+}
+
+func (m *Model) updateFormat(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.ForceQuit):
+			return tea.Quit
+		case key.Matches(msg, m.keys.Confirm):
+			t := m.tables[m.index]
+			if err := t.addFormatRule(t.column(t.model.ColumnCursor()), m.formatInput.Value()); err != nil {
+				m.formatErr = err
+				return nil
+			}
+			m.formatInput.Blur()
+			m.mode = "table"
+			return nil
+		case key.Matches(msg, m.keys.Cancel):
+			m.formatInput.Blur()
+			m.mode = "table"
+			return nil
+		}
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.formatInput, cmd = m.formatInput.Update(msg)
+	return cmd
+}
+
+func (m *Model) viewFormat() string {
+	view := m.formatInput.View()
+	if m.formatErr != nil {
+		view += "  " + m.theme.Error.Render(m.formatErr.Error())
+	}
+	return view
+}
+
+// addFormatRule adds a rule to a column of the table. An empty rule clears
+// the rules of the column.
+func (t *table) addFormatRule(col int, text string) error {
+	if strings.TrimSpace(text) == "" {
+		t.edit()
+		t.rules[col] = nil
+		t.set()
+		return nil
+	}
+
+	rule, err := parseFormatRule(text)
+	if err != nil {
+		return err
+	}
+	if !rule.heatmap {
+		if _, err := compileColumnFilter(rule.expr, t.data[0], col); err != nil {
+			return err
+		}
+	}
+
+	t.edit()
+	rules := t.rules[col]
+	if rule.heatmap {
+		// A column has a single heatmap.
+		rules = removeHeatmap(rules)
+	}
+	t.rules[col] = append(rules, rule)
+	t.set()
+	return nil
+}
+
+func removeHeatmap(rules []formatRule) []formatRule {
+	kept := make([]formatRule, 0, len(rules))
+	for _, r := range rules {
+		if !r.heatmap {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// parseFormatRule parses a format rule.
+func parseFormatRule(text string) (formatRule, error) {
+	fields := strings.Fields(text)
+	if fields[0] == "heatmap" {
+		return parseHeatmap(fields[1:])
+	}
+
+	spec, expr, ok := strings.Cut(" "+strings.TrimSpace(text)+" ", " if ")
+	if !ok || strings.TrimSpace(expr) == "" {
+		return formatRule{}, fmt.Errorf("expected COLOR [on COLOR] if CONDITION, or heatmap")
+	}
+
+	style, err := parseFormatStyle(strings.Fields(spec))
+	if err != nil {
+		return formatRule{}, err
+	}
+
+	return formatRule{expr: strings.TrimSpace(expr), style: style}, nil
+}
+
+// parseFormatStyle parses the colors of a rule, FG, FG on BG or on BG.
+func parseFormatStyle(fields []string) (lipgloss.Style, error) {
+	style := lipgloss.NewStyle()
+
+	var fg, bg string
+	switch {
+	case len(fields) == 1:
+		fg = fields[0]
+	case len(fields) == 2 && fields[0] == "on":
+		bg = fields[1]
+	case len(fields) == 3 && fields[1] == "on":
+		fg, bg = fields[0], fields[2]
+	default:
+		return style, fmt.Errorf("expected COLOR, COLOR on COLOR or on COLOR before if")
+	}
+
+	if fg != "" {
+		color, err := parseColor(fg)
+		if err != nil {
+			return style, err
+		}
+		style = style.Foreground(color)
+	}
+	if bg != "" {
+		color, err := parseColor(bg)
+		if err != nil {
+			return style, err
+		}
+		style = style.Background(color)
+	}
+	return style, nil
+}
+
+// parseColor parses a color name, an ANSI color number or a #rrggbb color.
+func parseColor(s string) (lipgloss.Color, error) {
+	if hex, ok := colorNames[s]; ok {
+		return lipgloss.Color(hex), nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 255 {
+		return lipgloss.Color(s), nil
+	}
+	if _, err := parseRGB(s); err == nil {
+		return lipgloss.Color(s), nil
+	}
+	return "", fmt.Errorf("unknown color %q", s)
+}
+
+func parseHeatmap(args []string) (formatRule, error) {
+	switch len(args) {
+	case 0:
+		args = defaultHeatmap[:]
+	case 2:
+	default:
+		return formatRule{}, fmt.Errorf("expected heatmap or heatmap LOW HIGH")
+	}
+
+	rule := formatRule{heatmap: true}
+	for i, arg := range args {
+		if hex, ok := colorNames[arg]; ok {
+			arg = hex
+		}
+		c, err := parseRGB(arg)
+		if err != nil {
+			return formatRule{}, fmt.Errorf("heatmap colors must be names or #rrggbb, got %q", args[i])
+		}
+		if i == 0 {
+			rule.low = c
+		} else {
+			rule.high = c
+		}
+	}
+	return rule, nil
+}
+
+func parseRGB(s string) (rgb, error) {
+	var c rgb
+	if len(s) != 7 || s[0] != '#' {
+		return c, fmt.Errorf("invalid color %q", s)
+	}
+	n, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return c, fmt.Errorf("invalid color %q", s)
+	}
+	return rgb{int(n >> 16), int(n >> 8 & 0xff), int(n & 0xff)}, nil
+}
+
+// blend returns the color at t between c and to, with t from 0 to 1.
+func (c rgb) blend(to rgb, t float64) rgb {
+	mix := func(a, b int) int {
+		return a + int(math.Round(float64(b-a)*t))
+	}
+	return rgb{mix(c.r, to.r), mix(c.g, to.g), mix(c.b, to.b)}
+}
+
+func (c rgb) color() lipgloss.Color {
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", c.r, c.g, c.b))
+}
+
+// contrast returns black or white, whichever is more readable on c.
+func (c rgb) contrast() lipgloss.Color {
+	if 299*c.r+587*c.g+114*c.b > 150*1000 {
+		return lipgloss.Color("#000000")
+	}
+	return lipgloss.Color("#ffffff")
+}
+
+// conditionalStyle is a format rule compiled for the current columns.
+type conditionalStyle struct {
+	match filter
+	style lipgloss.Style
+}
+
+// heatmap colors the numbers of a column between min and max.
+type heatmap struct {
+	rule     formatRule
+	min, max float64
+}
+
+func (h heatmap) style(value string) (lipgloss.Style, bool) {
+	n, ok := parseNumber(strings.TrimSpace(value))
+	if !ok {
+		return lipgloss.Style{}, false
+	}
+	var t float64
+	if h.max > h.min {
+		t = (n - h.min) / (h.max - h.min)
+	}
+	c := h.rule.low.blend(h.rule.high, t)
+	return lipgloss.NewStyle().Foreground(c.contrast()).Background(c.color()), true
+}
+
+// setCellStyle applies the format rules of the table to its model. Rules that
+// no longer compile, e.g. because a column they use was renamed or removed,
+// are turned off, and formatErr reports the first of them.
+func (t *table) setCellStyle() {
+	conditions := make([][]conditionalStyle, len(t.rules))
+	heatmaps := make([]*heatmap, len(t.rules))
+
+	var formatted bool
+	t.formatErr = nil
+	for col, rules := range t.rules {
+		for _, r := range rules {
+			if r.heatmap {
+				heatmaps[col] = t.newHeatmap(col, r)
+				formatted = true
+				continue
+			}
+			f, err := compileColumnFilter(r.expr, t.data[0], col)
+			if err != nil {
+				if t.formatErr == nil {
+					t.formatErr = fmt.Errorf("%s: %v", t.data[0][col], err)
+				}
+				continue
+			}
+			conditions[col] = append(conditions[col], conditionalStyle{match: f, style: r.style})
+			formatted = true
+		}
+	}
+
+	if !formatted {
+		t.model.SetCellStyle(nil)
+		return
+	}
+
+	data, rows, cols, transposed := t.data, t.rows, t.cols, t.transposed
+	t.model.SetCellStyle(func(row, col int, value string) (lipgloss.Style, bool) {
+		if transposed {
+			// The first column holds the titles and the header a column.
+			if col == 0 {
+				return lipgloss.Style{}, false
+			}
+			row, col = col-1, row+1
+		}
+		// The model may still show the rows of the previous call to set.
+		if row >= len(rows) || col >= len(cols) {
+			return lipgloss.Style{}, false
+		}
+		col = cols[col]
+
+		style, ok := lipgloss.NewStyle(), false
+		for _, c := range conditions[col] {
+			if c.match(data[rows[row]]) {
+				style, ok = c.style, true
+				break
+			}
+		}
+		if h := heatmaps[col]; h != nil {
+			if heat, isNumber := h.style(value); isNumber {
+				style, ok = style.Copy().Inherit(heat), true
+			}
+		}
+		return style, ok
+	})
+}
+
+// newHeatmap returns a heatmap between the smallest and the largest number of
+// a column in the rows shown.
+func (t *table) newHeatmap(col int, rule formatRule) *heatmap {
+	h := &heatmap{rule: rule}
+	first := true
+	for _, r := range t.rows {
+		n, ok := parseNumber(strings.TrimSpace(t.data[r][col]))
+		if !ok {
+			continue
+		}
+		if first || n < h.min {
+			h.min = n
+		}
+		if first || n > h.max {
+			h.max = n
+		}
+		first = false
+	}
+	return h
+}
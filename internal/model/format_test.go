@@ -0,0 +1,141 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/atye/wikitable/bubble"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestFormatRules(t *testing.T) {
+	data := [][]string{
+		{"Country", "Continent", "Population"},
+		{"Japan", "Asia", "125,700,000"},
+		{"Nepal", "Asia", "30,550,000"},
+		{"Iceland", "Europe", "387,758"},
+		{"Atlantis", "", ""},
+	}
+
+	tests := []struct {
+		rule string
+		col  int
+		want []lipgloss.TerminalColor
+	}{
+		{"red if > 1e7", 2, []lipgloss.TerminalColor{lipgloss.Color(colorNames["red"]), lipgloss.Color(colorNames["red"]), nil, nil}},
+		{"196 if Continent == \"Europe\"", 0, []lipgloss.TerminalColor{nil, nil, lipgloss.Color("196"), nil}},
+		{"#00ff00 if !~ /^[JN]/", 0, []lipgloss.TerminalColor{nil, nil, lipgloss.Color("#00ff00"), lipgloss.Color("#00ff00")}},
+		{"white on blue if empty(Continent)", 1, []lipgloss.TerminalColor{nil, nil, nil, lipgloss.Color(colorNames["white"])}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.rule, func(t *testing.T) {
+			sut := newTable(data, source{}, 80, 10, 0)
+			if err := sut.addFormatRule(tc.col, tc.rule); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			style := cellStyleOf(t, sut)
+			for row, want := range tc.want {
+				s, ok := style(row, tc.col, data[row+1][tc.col])
+				if want == nil {
+					if ok {
+						t.Errorf("expected row %d not to be styled", row)
+					}
+					continue
+				}
+				if !ok || s.GetForeground() != want {
+					t.Errorf("expected row %d to be %v, got %v", row, want, s.GetForeground())
+				}
+			}
+		})
+	}
+
+	t.Run("it colors a heatmap", func(t *testing.T) {
+		sut := newTable(data, source{}, 80, 10, 0)
+		if err := sut.addFormatRule(2, "heatmap #000000 #ffffff"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		style := cellStyleOf(t, sut)
+		want := []lipgloss.TerminalColor{lipgloss.Color("#ffffff"), lipgloss.Color("#3d3d3d"), lipgloss.Color("#000000")}
+		for row, w := range want {
+			if s, _ := style(row, 2, data[row+1][2]); s.GetBackground() != w {
+				t.Errorf("expected row %d to be %v, got %v", row, w, s.GetBackground())
+			}
+		}
+		if _, ok := style(3, 2, ""); ok {
+			t.Errorf("expected empty cell not to be styled")
+		}
+	})
+
+	t.Run("it compares the column itself when titles repeat or change", func(t *testing.T) {
+		data := [][]string{
+			{"Party", "Votes", "Votes"},
+			{"A", "500", "50"},
+			{"B", "50", "500"},
+		}
+		sut := newTable(data, source{}, 80, 10, 0)
+		if err := sut.addFormatRule(2, "red if > 100"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		check := func() {
+			t.Helper()
+			style := cellStyleOf(t, sut)
+			if _, ok := style(0, 2, "50"); ok {
+				t.Errorf("expected row 0 not to be styled")
+			}
+			if s, ok := style(1, 2, "500"); !ok || s.GetForeground() != lipgloss.Color(colorNames["red"]) {
+				t.Errorf("expected row 1 to be red, got %v", s.GetForeground())
+			}
+		}
+		check()
+
+		sut.setCell(0, 2, "Votes (%)")
+		if sut.formatErr != nil {
+			t.Errorf("expected the rule to be kept on, got %v", sut.formatErr)
+		}
+		check()
+	})
+
+	t.Run("it clears rules", func(t *testing.T) {
+		sut := newTable(data, source{}, 80, 10, 0)
+		if err := sut.addFormatRule(2, "heatmap"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := sut.addFormatRule(2, ""); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(sut.rules[2]) != 0 {
+			t.Errorf("expected no rules, got %d", len(sut.rules[2]))
+		}
+	})
+
+	errors := []string{
+		"red",
+		"red if",
+		"mauve if > 3",
+		"red if Nope > 3",
+		"heatmap red",
+		"heatmap 1 2",
+	}
+	for _, rule := range errors {
+		t.Run("it returns error on "+rule, func(t *testing.T) {
+			sut := newTable(data, source{}, 80, 10, 0)
+			if err := sut.addFormatRule(2, rule); err == nil {
+				t.Errorf("expected error, got nil")
+			}
+		})
+	}
+}
+
+// cellStyleOf returns the cell style the table set in its model.
+func cellStyleOf(t *testing.T, sut *table) bubble.CellStyle {
+	t.Helper()
+
+	style := sut.model.CellStyle()
+	if style == nil {
+		t.Fatal("expected a cell style")
+	}
+	return style
+}
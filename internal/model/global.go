@@ -0,0 +1,221 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// maxHits limits the number of results of a global search.
+const maxHits = 1000
+
+// globalSearch searches the cells of every loaded table and lists the hits.
+type globalSearch struct {
+	input    textinput.Model
+	regex    bool
+	re       *regexp.Regexp
+	err      error
+	hits     []hit
+	selected int
+	offset   int
+}
+
+// hit is a cell matching a global search.
+type hit struct {
+	table int
+	row   int
+	col   int
+	value string
+	match []int
+}
+
+func newGlobalSearchInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = "Search all tables: "
+	return input
+}
+
+func (m *Model) openGlobalSearch() tea.Cmd {
+	m.global.input.SetValue("")
+	m.global.re = nil
+	m.global.err = nil
+	m.global.hits = nil
+	m.global.selected = 0
+	m.global.offset = 0
+	return m.global.input.Focus()
+}
+
+func (m *Model) updateGlobalSearch(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.ForceQuit):
+			return tea.Quit
+		case key.Matches(msg, m.keys.Cancel):
+			m.global.input.Blur()
+			m.mode = "table"
+			return nil
+		case key.Matches(msg, m.keys.Confirm):
+			if len(m.global.hits) == 0 {
+				return nil
+			}
+			m.global.input.Blur()
+			m.jumpToHit(m.global.hits[m.global.selected])
+			m.mode = "table"
+			return nil
+		case key.Matches(msg, m.keys.PrevHit):
+			m.selectHit(m.global.selected - 1)
+			return nil
+		case key.Matches(msg, m.keys.NextHit):
+			m.selectHit(m.global.selected + 1)
+			return nil
+		case key.Matches(msg, m.keys.ToggleRegex):
+			m.global.regex = !m.global.regex
+			m.findHits()
+			return nil
+		}
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
+		m.selectHit(m.global.selected)
+		return nil
+	}
+
+	value := m.global.input.Value()
+	var cmd tea.Cmd
+	m.global.input, cmd = m.global.input.Update(msg)
+	if m.global.input.Value() != value {
+		m.findHits()
+	}
+	return cmd
+}
+
+// findHits compiles the query and collects the matching cells of every table.
+func (m *Model) findHits() {
+	m.global.hits = nil
+	m.global.selected = 0
+	m.global.offset = 0
+	m.global.re, m.global.err = compileQuery(m.global.input.Value(), m.global.regex)
+	if m.global.re == nil {
+		return
+	}
+
+	for i, t := range m.tables {
+		for r, row := range t.model.Rows() {
+			for c, value := range row {
+				match := m.global.re.FindStringIndex(value)
+				if match == nil {
+					continue
+				}
+				m.global.hits = append(m.global.hits, hit{table: i, row: r, col: c, value: value, match: match})
+				if len(m.global.hits) == maxHits {
+					return
+				}
+			}
+		}
+	}
+}
+
+// selectHit selects the hit at index i and scrolls the list to keep it
+// visible.
+func (m *Model) selectHit(i int) {
+	if len(m.global.hits) == 0 {
+		m.global.selected, m.global.offset = 0, 0
+		return
+	}
+	m.global.selected = clamp(i, 0, len(m.global.hits)-1)
+	height := m.hitsHeight()
+	if m.global.selected < m.global.offset {
+		m.global.offset = m.global.selected
+	}
+	if m.global.selected >= m.global.offset+height {
+		m.global.offset = m.global.selected - height + 1
+	}
+}
+
+// jumpToHit switches to the table of h and selects its cell. The query
+// becomes the current search so n and N continue from the hit.
+func (m *Model) jumpToHit(h hit) {
+	m.index = h.table
+	m.tables[m.index].model.SelectCell(h.row, h.col)
+
+	m.search.re = m.global.re
+	m.search.regex = m.global.regex
+	m.search.backward = false
+	m.search.input.SetValue(m.global.input.Value())
+	m.setHighlight()
+}
+
+func (m *Model) viewGlobalSearch() string {
+	var b strings.Builder
+	b.WriteString(m.global.input.View())
+	if m.global.regex {
+		b.WriteString(m.theme.Blurred.Render("  (regex)"))
+	}
+	b.WriteString("\n")
+
+	switch {
+	case m.global.err != nil:
+		b.WriteString(m.theme.Error.Render(m.global.err.Error()))
+	case m.global.re != nil:
+		count := fmt.Sprintf("%d matches", len(m.global.hits))
+		if len(m.global.hits) == maxHits {
+			count = fmt.Sprintf("first %d matches", maxHits)
+		}
+		b.WriteString(m.theme.Blurred.Render(count))
+	}
+	b.WriteString("\n")
+
+	end := min(m.global.offset+m.hitsHeight(), len(m.global.hits))
+	for i := m.global.offset; i < end; i++ {
+		h := m.global.hits[i]
+		location := fmt.Sprintf("table %d  %s: ", h.table+1, m.tables[h.table].cellLocation(h.row, h.col))
+		line := runewidth.Truncate(location+snippet(h.value, h.match), max(m.width-2, 20), "…")
+		if i == m.global.selected {
+			b.WriteString(m.theme.Focused.Render("> " + line))
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+
+	list := lipgloss.NewStyle().Height(m.hitsHeight() + 2).MaxHeight(m.hitsHeight() + 2).Render(b.String())
+	return list + "\n" + m.shortHelpView()
+}
+
+// cellLocation names a cell of the model by the number of its row, as in the
+// row numbers, and the title of its column. The first column of a transposed
+// table holds titles.
+func (t *table) cellLocation(row, col int) string {
+	r, c := t.dataCell(row, col)
+	if r == 0 {
+		return "title of " + t.data[0][c]
+	}
+	return fmt.Sprintf("row %d  %s", t.ids[r], t.data[0][c])
+}
+
+// hitsHeight returns the number of hits that fit between the prompt and the
+// short help.
+func (m *Model) hitsHeight() int {
+	return max(m.height-3, 1)
+}
+
+// snippet returns the part of value around match on a single line, keeping
+// a little of the text before the match for context, measured in display
+// width so that grapheme clusters are not split.
+func snippet(value string, match []int) string {
+	const context = 20
+
+	value = strings.ReplaceAll(value, "\n", " ")
+	start := match[0]
+	width := runewidth.StringWidth(value[:start])
+	if width <= context {
+		return value
+	}
+	return runewidth.TruncateLeft(value[:start], width-context, "…") + value[start:]
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSnippet(t *testing.T) {
+	value := strings.Repeat("東京", 10) + "match"
+	got := snippet(value, []int{strings.Index(value, "match"), len(value)})
+	if want := "…" + strings.Repeat("東京", 5) + "match"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestCellLocation(t *testing.T) {
+	data := [][]string{
+		{"city", "population"},
+		{"Lyon", "500000"},
+		{"Paris", "2100000"},
+	}
+	sut := newTable(data, source{}, 80, 10, 0)
+	sut.sortBy(0, true)
+
+	tests := []struct {
+		transposed bool
+		row, col   int
+		want       string
+	}{
+		{false, 0, 1, "row 2  population"},
+		{false, 1, 0, "row 1  city"},
+		{true, 0, 0, "title of population"},
+		{true, 0, 1, "row 2  population"},
+	}
+
+	for _, tc := range tests {
+		if sut.transposed != tc.transposed {
+			sut.transpose()
+		}
+		if got := sut.cellLocation(tc.row, tc.col); got != tc.want {
+			t.Errorf("expected %q for (%d, %d), got %q", tc.want, tc.row, tc.col, got)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"github.com/atye/wikitable/bubble"
+)
+
+// A snapshot is the state of a table before or after an edit. The data and
+// ids are shared with the table, so the table never changes them in place:
+// every edit replaces the slices it changes, down to the rows it changes. The
+// settings of the columns are small, so they are copied.
+type snapshot struct {
+	data     [][]string
+	ids      []int
+	widths   []int
+	aligns   []bubble.Alignment
+	rules    [][]formatRule
+	hidden   []bool
+	sortKeys []sortKey
+	filter   string
+}
+
+// history holds the states of a table before the edits that can be undone,
+// most recent last, and after the edits that were undone and can be redone.
+type history struct {
+	undo []snapshot
+	redo []snapshot
+}
+
+// snapshot returns the current state of the table.
+func (t *table) snapshot() snapshot {
+	return snapshot{
+		data:     t.data,
+		ids:      t.ids,
+		widths:   t.widths,
+		aligns:   t.aligns,
+		rules:    t.rules,
+		hidden:   t.hidden,
+		sortKeys: t.sortKeys,
+		filter:   t.filter,
+	}.clone()
+}
+
+// restore sets the state of the table to s.
+func (t *table) restore(s snapshot) {
+	s = s.clone()
+	t.data = s.data
+	t.ids = s.ids
+	t.widths = s.widths
+	t.aligns = s.aligns
+	t.rules = s.rules
+	t.hidden = s.hidden
+	t.sortKeys = s.sortKeys
+	t.filter = s.filter
+	t.set()
+}
+
+// clone returns a copy of s with its own settings of the columns, which
+// shares the data and ids with s.
+func (s snapshot) clone() snapshot {
+	rules := make([][]formatRule, len(s.rules))
+	for i, r := range s.rules {
+		rules[i] = append([]formatRule(nil), r...)
+	}
+	s.widths = append([]int(nil), s.widths...)
+	s.aligns = append([]bubble.Alignment(nil), s.aligns...)
+	s.rules = rules
+	s.hidden = append([]bool(nil), s.hidden...)
+	s.sortKeys = append([]sortKey(nil), s.sortKeys...)
+	return s
+}
+
+// edit records the current state of the table before it is edited. Edits
+// that were undone can no longer be redone.
+func (t *table) edit() {
+	t.history.undo = append(t.history.undo, t.snapshot())
+	t.history.redo = nil
+}
+
+// undo reverts the last edit of the table. It reports false if there is none.
+func (t *table) undo() bool {
+	n := len(t.history.undo)
+	if n == 0 {
+		return false
+	}
+	t.history.redo = append(t.history.redo, t.snapshot())
+	s := t.history.undo[n-1]
+	t.history.undo = t.history.undo[:n-1]
+	t.restore(s)
+	return true
+}
+
+// redo applies the last edit that was undone. It reports false if there is
+// none.
+func (t *table) redo() bool {
+	n := len(t.history.redo)
+	if n == 0 {
+		return false
+	}
+	t.history.undo = append(t.history.undo, t.snapshot())
+	s := t.history.redo[n-1]
+	t.history.redo = t.history.redo[:n-1]
+	t.restore(s)
+	return true
+}
+
+// historyPosition returns the number of edits of the table that are applied
+// and the number of edits in its history.
+func (t *table) historyPosition() (int, int) {
+	return len(t.history.undo), len(t.history.undo) + len(t.history.redo)
+}
@@ -0,0 +1,95 @@
+package model
+
+// insert inserts an empty row or column next to the cursor, depending on the
+// cursor mode: below or right of it if after is set, and above or left of it
+// otherwise. The rows of a transposed table are columns of data and its
+// columns rows. Left and right are mirrored in a right-to-left table, like
+// the columns.
+func (t *table) insert(after bool) {
+	cursor := t.model.Cursor()
+	switch {
+	case t.model.CursorMode() == "row" && !t.transposed:
+		pos := len(t.data)
+		if len(t.rows) > 0 {
+			pos = t.rows[cursor]
+		}
+		t.insertRow(pos, after, make([]string, len(t.data[0])))
+	case t.model.CursorMode() == "row":
+		if len(t.model.Rows()) > 0 {
+			t.insertColumn(t.cols[cursor+1], after)
+		}
+	case !t.transposed:
+		if t.model.RTL() {
+			after = !after
+		}
+		pos := len(t.data[0])
+		if len(t.cols) > 0 {
+			pos = t.cols[cursor]
+		}
+		t.insertColumn(pos, after)
+	case cursor > 0:
+		// The first column holds the titles of the columns.
+		t.insertRow(t.rows[cursor-1], after, make([]string, len(t.data[0])))
+	}
+}
+
+// duplicateRow inserts a copy of the selected row under it.
+func (t *table) duplicateRow() {
+	if len(t.model.Rows()) == 0 || len(t.model.Columns()) == 0 {
+		return
+	}
+	r, _ := t.dataCell(t.model.RowCursor(), t.model.ColumnCursor())
+	if r == 0 {
+		return
+	}
+	t.insertRow(r, true, append([]string(nil), t.data[r]...))
+}
+
+// insertRow inserts a row of data before the row at pos, or after it if after
+// is set, and selects it. The new row is numbered after the last row.
+func (t *table) insertRow(pos int, after bool, row []string) {
+	if after && pos < len(t.data) {
+		pos++
+	}
+	pos = max(pos, 1)
+	t.edit()
+
+	id := 0
+	for _, i := range t.ids {
+		id = max(id, i)
+	}
+	t.data = insertAt(t.data, pos, row)
+	t.ids = insertAt(t.ids, pos, id+1)
+	t.set()
+	t.selectRow(pos)
+}
+
+// insertColumn inserts an empty column of data before the column at pos, or
+// after it if after is set, and selects it.
+func (t *table) insertColumn(pos int, after bool) {
+	if after && pos < len(t.data[0]) {
+		pos++
+	}
+	t.edit()
+
+	data := make([][]string, len(t.data))
+	for i, row := range t.data {
+		data[i] = insertAt(row, pos, "")
+	}
+	t.data = data
+	t.widths = insertAt(t.widths, pos, 0)
+	t.aligns = insertAt(t.aligns, pos, autoAlign)
+	t.rules = insertAt(t.rules, pos, nil)
+	t.hidden = insertAt(t.hidden, pos, false)
+	t.insertSortColumn(pos)
+	t.set()
+	t.selectColumn(pos)
+}
+
+// insertAt returns a copy of s with v inserted at index i.
+func insertAt[T any](s []T, i int, v T) []T {
+	inserted := make([]T, 0, len(s)+1)
+	inserted = append(inserted, s[:i]...)
+	inserted = append(inserted, v)
+	return append(inserted, s[i:]...)
+}
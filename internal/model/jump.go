@@ -0,0 +1,198 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/atye/wikitable/bubble"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// jump is the prompt that moves the cursor to a row by its number, as in
+// :57, or to the column whose title best matches a fuzzy query, as in
+// :popden for "Population density". A query quoted with backticks, as in
+// :`2019`, is always a column title, like the columns of filters.
+type jump struct {
+	input textinput.Model
+	err   error
+
+	// The cell selected when the prompt was opened, which is restored if the
+	// jump is cancelled.
+	row int
+	col int
+}
+
+func newJumpInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = ":"
+	input.Placeholder = "row number, column title or `title`"
+	return input
+}
+
+func (m *Model) openJump() tea.Cmd {
+	t := m.tables[m.index]
+
+	m.jump.row = t.model.RowCursor()
+	m.jump.col = t.model.ColumnCursor()
+	m.jump.input.SetValue("")
+	m.jump.err = nil
+	return m.jump.input.Focus()
+}
+
+func (m *Model) updateJump(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.ForceQuit):
+			return tea.Quit
+		case key.Matches(msg, m.keys.Confirm):
+			if query := strings.TrimSpace(m.jump.input.Value()); query != "" {
+				if err := m.tables[m.index].jumpTo(query); err != nil {
+					m.jump.err = err
+					return nil
+				}
+			}
+			m.jump.input.Blur()
+			m.mode = "table"
+			return nil
+		case key.Matches(msg, m.keys.Cancel):
+			m.jump.input.Blur()
+			m.tables[m.index].model.SelectCell(m.jump.row, m.jump.col)
+			m.mode = "table"
+			return nil
+		}
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
+		return nil
+	}
+
+	value := m.jump.input.Value()
+	var cmd tea.Cmd
+	m.jump.input, cmd = m.jump.input.Update(msg)
+	if m.jump.input.Value() != value {
+		m.previewJump()
+	}
+	return cmd
+}
+
+// previewJump selects the cell the query jumps to as it is typed. Errors are
+// only shown when the jump is confirmed.
+func (m *Model) previewJump() {
+	t := m.tables[m.index]
+	t.model.SelectCell(m.jump.row, m.jump.col)
+	m.jump.err = nil
+	if query := strings.TrimSpace(m.jump.input.Value()); query != "" {
+		_ = t.jumpTo(query)
+	}
+}
+
+func (m *Model) viewJump() string {
+	view := m.jump.input.View()
+	if m.jump.err != nil {
+		view += "  " + m.theme.Error.Render(m.jump.err.Error())
+	}
+	return view
+}
+
+// jumpTo selects the row numbered query, or the column whose title best
+// matches it. A leading backtick makes query a title even if it is a number,
+// and the closing one is optional.
+func (t *table) jumpTo(query string) error {
+	if strings.HasPrefix(query, "`") {
+		return t.jumpToColumn(strings.TrimSuffix(query[1:], "`"))
+	}
+	if n, err := strconv.Atoi(query); err == nil {
+		return t.jumpToRow(n)
+	}
+	return t.jumpToColumn(query)
+}
+
+// jumpToRow selects the row numbered n when the table was read. The rows of a
+// transposed table are its columns.
+func (t *table) jumpToRow(n int) error {
+	for _, r := range t.rows {
+		if t.ids[r] == n {
+			t.selectRow(r)
+			return nil
+		}
+	}
+	return fmt.Errorf("row %d is not shown", n)
+}
+
+// jumpToColumn selects the column shown whose title best matches query. Ties
+// go to the shortest title, then to the first column.
+func (t *table) jumpToColumn(query string) error {
+	best, bestScore := -1, 0
+	for i, c := range t.cols {
+		score, ok := fuzzyScore(query, t.data[0][c])
+		if !ok {
+			continue
+		}
+		if best < 0 || score > bestScore ||
+			score == bestScore && bubble.Width(t.data[0][c]) < bubble.Width(t.data[0][t.cols[best]]) {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return fmt.Errorf("no column matches %q", query)
+	}
+
+	t.selectColumn(t.cols[best])
+	return nil
+}
+
+// fuzzyScore reports whether the letters of query appear in title in order,
+// ignoring case and the spaces of query, and scores how well they match.
+// Letters at the start of the title or of a word, and runs of consecutive
+// letters, score higher, and gaps between letters score lower.
+func fuzzyScore(query, title string) (int, bool) {
+	q := []rune(strings.ToLower(strings.Join(strings.Fields(query), "")))
+	s := []rune(strings.ToLower(title))
+	if len(q) == 0 {
+		return 0, false
+	}
+
+	best, found := 0, false
+	for start, r := range s {
+		if r != q[0] {
+			continue
+		}
+		if score, ok := fuzzyScoreFrom(q, s, start); ok && (!found || score > best) {
+			best, found = score, true
+		}
+	}
+	return best, found
+}
+
+// fuzzyScoreFrom scores the match of q in s that starts at start and takes
+// the first occurrence of each following letter.
+func fuzzyScoreFrom(q, s []rune, start int) (int, bool) {
+	var score, matched int
+	prev := -1
+	for i := start; i < len(s) && matched < len(q); i++ {
+		if s[i] != q[matched] {
+			continue
+		}
+		score++
+		switch {
+		case i == 0:
+			score += 8
+		case !unicode.IsLetter(s[i-1]) && !unicode.IsDigit(s[i-1]):
+			score += 4
+		}
+		switch {
+		case prev < 0:
+		case i == prev+1:
+			score += 3
+		default:
+			score -= min(i-prev-1, 3)
+		}
+		prev = i
+		matched++
+	}
+	return score, matched == len(q)
+}
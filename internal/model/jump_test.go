@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query  string
+		better string
+		worse  string
+	}{
+		{"pop", "Population", "Top position"},
+		{"popden", "Population density", "Population"},
+		{"gdp", "GDP (nominal)", "Gold deposits"},
+		{"area", "Area (km²)", "Land area"},
+		{"pop den", "Population density", "Population"},
+		{"yr", "Year", "Country"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			better, ok := fuzzyScore(tc.query, tc.better)
+			if !ok {
+				t.Fatalf("expected %q to match %q", tc.query, tc.better)
+			}
+			if worse, ok := fuzzyScore(tc.query, tc.worse); ok && worse >= better {
+				t.Errorf("expected %q to match %q (%d) better than %q (%d)", tc.query, tc.better, better, tc.worse, worse)
+			}
+		})
+	}
+
+	t.Run("it doesn't match letters out of order", func(t *testing.T) {
+		if _, ok := fuzzyScore("dp", "Population density"); ok {
+			t.Errorf("expected no match")
+		}
+	})
+}
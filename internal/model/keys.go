@@ -0,0 +1,662 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/atye/wikitable/bubble"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// KeyMap defines the keybindings of the model, including the ones of the
+// table widget.
+type KeyMap struct {
+	Table bubble.KeyMap
+
+	ForceQuit key.Binding
+
+	// Input form
+	NextInput key.Binding
+	PrevInput key.Binding
+	Submit    key.Binding
+
+	// Tables
+	Quit             key.Binding
+	NewTables        key.Binding
+	NextTable        key.Binding
+	PrevTable        key.Binding
+	Left             key.Binding
+	Right            key.Binding
+	SwitchCursorMode key.Binding
+	Delete           key.Binding
+	InsertAfter      key.Binding
+	InsertBefore     key.Binding
+	DuplicateRow     key.Binding
+	EditCell         key.Binding
+	EditTitle        key.Binding
+	Reset            key.Binding
+	Undo             key.Binding
+	Redo             key.Binding
+	DeleteTable      key.Binding
+	SortAscending    key.Binding
+	SortDescending   key.Binding
+	CellDetail       key.Binding
+	RowDetail        key.Binding
+	Search           key.Binding
+	SearchBackward   key.Binding
+	NextMatch        key.Binding
+	PrevMatch        key.Binding
+	ClearSearch      key.Binding
+	GlobalSearch     key.Binding
+	Filter           key.Binding
+	Jump             key.Binding
+	CycleAlign       key.Binding
+	Format           key.Binding
+	WidenColumn      key.Binding
+	NarrowColumn     key.Binding
+	MoveColumnLeft   key.Binding
+	MoveColumnRight  key.Binding
+	HideColumn       key.Binding
+	ShowColumns      key.Binding
+	ToggleBorders    key.Binding
+	ToggleZebra      key.Binding
+	ToggleCompact    key.Binding
+	ToggleFit        key.Binding
+	ToggleWrap       key.Binding
+	ToggleNumbers    key.Binding
+	Transpose        key.Binding
+	VisualRows       key.Binding
+	VisualCells      key.Binding
+	Help             key.Binding
+
+	// Prompts and popups
+	Confirm      key.Binding
+	Cancel       key.Binding
+	ToggleRegex  key.Binding
+	NextHit      key.Binding
+	PrevHit      key.Binding
+	CloseDetail  key.Binding
+	ToggleDetail key.Binding
+	ScrollDetail key.Binding
+	CloseHelp    key.Binding
+
+	// Visual selection
+	Yank         key.Binding
+	Export       key.Binding
+	KeepSelected key.Binding
+	CloseVisual  key.Binding
+
+	// Hidden columns panel
+	NextHidden   key.Binding
+	PrevHidden   key.Binding
+	ShowColumn   key.Binding
+	CloseColumns key.Binding
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	table := bubble.DefaultKeyMap()
+	table.LineDown.SetKeys("enter", "down", "j")
+	// ctrl+d deletes rows and columns.
+	table.HalfPageDown.SetKeys("d")
+
+	return KeyMap{
+		Table: table,
+		ForceQuit: key.NewBinding(
+			key.WithKeys("ctrl+c"),
+			key.WithHelp("ctrl+c", "quit"),
+		),
+		NextInput: key.NewBinding(
+			key.WithKeys("tab", "enter", "down"),
+			key.WithHelp("tab/enter/↓", "next field"),
+		),
+		PrevInput: key.NewBinding(
+			key.WithKeys("up"),
+			key.WithHelp("↑", "previous field"),
+		),
+		Submit: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "submit"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q"),
+			key.WithHelp("q", "quit"),
+		),
+		NewTables: key.NewBinding(
+			key.WithKeys("ctrl+n"),
+			key.WithHelp("ctrl+n", "new tables"),
+		),
+		NextTable: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "next table"),
+		),
+		PrevTable: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "previous table"),
+		),
+		Left: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "cell left"),
+		),
+		Right: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "cell right"),
+		),
+		SwitchCursorMode: key.NewBinding(
+			key.WithKeys("ctrl+k"),
+			key.WithHelp("ctrl+k", "row/column mode"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("ctrl+d"),
+			key.WithHelp("ctrl+d", "delete row/column"),
+		),
+		Reset: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "reset table"),
+		),
+		InsertAfter: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "insert below/right"),
+		),
+		InsertBefore: key.NewBinding(
+			key.WithKeys("I"),
+			key.WithHelp("I", "insert above/left"),
+		),
+		DuplicateRow: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "duplicate row"),
+		),
+		EditCell: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "edit cell"),
+		),
+		EditTitle: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "edit title"),
+		),
+		Undo: key.NewBinding(
+			key.WithKeys("ctrl+z"),
+			key.WithHelp("ctrl+z", "undo"),
+		),
+		Redo: key.NewBinding(
+			key.WithKeys("ctrl+y"),
+			key.WithHelp("ctrl+y", "redo"),
+		),
+		DeleteTable: key.NewBinding(
+			key.WithKeys("ctrl+t"),
+			key.WithHelp("ctrl+t", "delete table"),
+		),
+		SortAscending: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort ▲ (column mode)"),
+		),
+		SortDescending: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "sort ▼ (column mode)"),
+		),
+		CellDetail: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "show cell"),
+		),
+		RowDetail: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "show row"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search"),
+		),
+		SearchBackward: key.NewBinding(
+			key.WithKeys("#"),
+			key.WithHelp("#", "search backward (vim ?)"),
+		),
+		NextMatch: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next match"),
+		),
+		PrevMatch: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "previous match"),
+		),
+		ClearSearch: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear search"),
+		),
+		GlobalSearch: key.NewBinding(
+			key.WithKeys("ctrl+f"),
+			key.WithHelp("ctrl+f", "search all tables"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("&"),
+			key.WithHelp("&", "filter rows"),
+		),
+		Jump: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":", "jump to row or column"),
+		),
+		CycleAlign: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "cycle column alignment"),
+		),
+		Format: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "format column"),
+		),
+		WidenColumn: key.NewBinding(
+			key.WithKeys(">"),
+			key.WithHelp(">", "widen column"),
+		),
+		NarrowColumn: key.NewBinding(
+			key.WithKeys("<"),
+			key.WithHelp("<", "narrow column"),
+		),
+		MoveColumnLeft: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "move column left"),
+		),
+		MoveColumnRight: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "move column right"),
+		),
+		HideColumn: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "hide column"),
+		),
+		ShowColumns: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "hidden columns"),
+		),
+		ToggleBorders: key.NewBinding(
+			key.WithKeys("|"),
+			key.WithHelp("|", "toggle borders"),
+		),
+		ToggleZebra: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "toggle zebra stripes"),
+		),
+		ToggleCompact: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "toggle compact"),
+		),
+		ToggleFit: key.NewBinding(
+			key.WithKeys("="),
+			key.WithHelp("=", "toggle auto-fit"),
+		),
+		ToggleWrap: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "toggle wrap"),
+		),
+		ToggleNumbers: key.NewBinding(
+			key.WithKeys("ctrl+l"),
+			key.WithHelp("ctrl+l", "toggle row numbers"),
+		),
+		Transpose: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "transpose"),
+		),
+		VisualRows: key.NewBinding(
+			key.WithKeys("V"),
+			key.WithHelp("V", "select rows"),
+		),
+		VisualCells: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "select cells"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "help"),
+		),
+		Confirm: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "confirm"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+		ToggleRegex: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "toggle regex"),
+		),
+		NextHit: key.NewBinding(
+			key.WithKeys("down", "ctrl+n"),
+			key.WithHelp("↓/ctrl+n", "next match"),
+		),
+		PrevHit: key.NewBinding(
+			key.WithKeys("up", "ctrl+p"),
+			key.WithHelp("↑/ctrl+p", "previous match"),
+		),
+		CloseDetail: key.NewBinding(
+			key.WithKeys("esc", "q", "o", "O"),
+			key.WithHelp("esc", "close"),
+		),
+		ToggleDetail: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "cell/row"),
+		),
+		ScrollDetail: key.NewBinding(
+			key.WithKeys("up", "down", "k", "j", "pgup", "pgdown", "b", "f", "u", "d"),
+			key.WithHelp("↑/↓", "scroll"),
+		),
+		CloseHelp: key.NewBinding(
+			key.WithKeys("esc", "?", "q"),
+			key.WithHelp("esc/?", "close help"),
+		),
+		Yank: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("ctrl+s", "export"),
+		),
+		KeepSelected: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "keep selected"),
+		),
+		CloseVisual: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "stop selecting"),
+		),
+		NextHidden: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "next"),
+		),
+		PrevHidden: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "previous"),
+		),
+		ShowColumn: key.NewBinding(
+			key.WithKeys("enter", " "),
+			key.WithHelp("enter", "show column"),
+		),
+		CloseColumns: key.NewBinding(
+			key.WithKeys("esc", "q", "X"),
+			key.WithHelp("esc", "close"),
+		),
+	}
+}
+
+// NewKeyMap returns the default keybindings with the keys of the actions in
+// bindings replaced. An action bound to no keys is disabled. It returns an
+// error for unknown actions and keys, for digits bound to table actions, which
+// are used for counts, and for keys bound to more than one action that can be
+// used at the same time.
+func NewKeyMap(bindings map[string][]string) (KeyMap, error) {
+	km := DefaultKeyMap()
+	actions := km.actions()
+
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		action, ok := findKeyAction(actions, name)
+		if !ok {
+			return KeyMap{}, fmt.Errorf("unknown key action %q", name)
+		}
+
+		keys := make([]string, len(bindings[name]))
+		for i, k := range bindings[name] {
+			if k == "space" {
+				k = " "
+			}
+			if !validKey(k) {
+				return KeyMap{}, fmt.Errorf("invalid key %q for action %q", k, name)
+			}
+			if containsString(countGroups, action.group) && len(k) == 1 && k[0] >= '0' && k[0] <= '9' {
+				return KeyMap{}, fmt.Errorf("key %q for action %q is reserved for counts", k, name)
+			}
+			keys[i] = k
+		}
+
+		action.binding.SetKeys(keys...)
+		action.binding.SetHelp(keysHelp(keys), action.binding.Help().Desc)
+	}
+
+	if err := checkConflicts(actions); err != nil {
+		return KeyMap{}, err
+	}
+	return km, nil
+}
+
+// countGroups lists the groups of bindings used where digits type a count.
+var countGroups = []string{"table", "selection", "visual"}
+
+// keyAction is a configurable keybinding with its name in the config file and
+// the group of bindings it is used with.
+type keyAction struct {
+	name    string
+	group   string
+	binding *key.Binding
+}
+
+// keyScreens lists the groups of bindings that are used at the same time and
+// so must not share keys. The selection group holds the table bindings that
+// are also used while selecting rows or cells.
+var keyScreens = [][]string{
+	{"all", "input"},
+	{"all", "submit"},
+	{"all", "table", "selection"},
+	{"all", "prompt"},
+	{"all", "prompt", "hits"},
+	{"all", "detail"},
+	{"all", "help"},
+	{"all", "columns"},
+	{"all", "selection", "visual"},
+}
+
+func (km *KeyMap) actions() []keyAction {
+	return []keyAction{
+		{"force_quit", "all", &km.ForceQuit},
+		{"next_input", "input", &km.NextInput},
+		{"previous_input", "input", &km.PrevInput},
+		{"submit", "submit", &km.Submit},
+		{"quit", "table", &km.Quit},
+		{"new_tables", "table", &km.NewTables},
+		{"next_table", "table", &km.NextTable},
+		{"previous_table", "table", &km.PrevTable},
+		{"left", "selection", &km.Left},
+		{"right", "selection", &km.Right},
+		{"switch_cursor_mode", "selection", &km.SwitchCursorMode},
+		{"delete", "selection", &km.Delete},
+		{"insert_after", "table", &km.InsertAfter},
+		{"insert_before", "table", &km.InsertBefore},
+		{"duplicate_row", "table", &km.DuplicateRow},
+		{"edit_cell", "table", &km.EditCell},
+		{"edit_title", "table", &km.EditTitle},
+		{"reset", "table", &km.Reset},
+		{"undo", "table", &km.Undo},
+		{"redo", "table", &km.Redo},
+		{"delete_table", "table", &km.DeleteTable},
+		{"sort_ascending", "table", &km.SortAscending},
+		{"sort_descending", "table", &km.SortDescending},
+		{"cell_detail", "table", &km.CellDetail},
+		{"row_detail", "table", &km.RowDetail},
+		{"search", "table", &km.Search},
+		{"search_backward", "table", &km.SearchBackward},
+		{"next_match", "table", &km.NextMatch},
+		{"previous_match", "table", &km.PrevMatch},
+		{"clear_search", "table", &km.ClearSearch},
+		{"global_search", "table", &km.GlobalSearch},
+		{"filter", "table", &km.Filter},
+		{"jump", "table", &km.Jump},
+		{"cycle_align", "table", &km.CycleAlign},
+		{"format", "table", &km.Format},
+		{"widen_column", "table", &km.WidenColumn},
+		{"narrow_column", "table", &km.NarrowColumn},
+		{"move_column_left", "table", &km.MoveColumnLeft},
+		{"move_column_right", "table", &km.MoveColumnRight},
+		{"hide_column", "table", &km.HideColumn},
+		{"show_columns", "table", &km.ShowColumns},
+		{"toggle_borders", "table", &km.ToggleBorders},
+		{"toggle_zebra", "table", &km.ToggleZebra},
+		{"toggle_compact", "table", &km.ToggleCompact},
+		{"toggle_fit", "table", &km.ToggleFit},
+		{"toggle_wrap", "table", &km.ToggleWrap},
+		{"toggle_row_numbers", "table", &km.ToggleNumbers},
+		{"transpose", "table", &km.Transpose},
+		{"visual_rows", "selection", &km.VisualRows},
+		{"visual_cells", "selection", &km.VisualCells},
+		{"help", "table", &km.Help},
+		{"line_up", "selection", &km.Table.LineUp},
+		{"line_down", "selection", &km.Table.LineDown},
+		{"page_up", "selection", &km.Table.PageUp},
+		{"page_down", "selection", &km.Table.PageDown},
+		{"half_page_up", "selection", &km.Table.HalfPageUp},
+		{"half_page_down", "selection", &km.Table.HalfPageDown},
+		{"goto_top", "selection", &km.Table.GotoTop},
+		{"goto_bottom", "selection", &km.Table.GotoBottom},
+		{"confirm", "prompt", &km.Confirm},
+		{"cancel", "prompt", &km.Cancel},
+		{"toggle_regex", "prompt", &km.ToggleRegex},
+		{"next_hit", "hits", &km.NextHit},
+		{"previous_hit", "hits", &km.PrevHit},
+		{"close_detail", "detail", &km.CloseDetail},
+		{"toggle_detail", "detail", &km.ToggleDetail},
+		{"close_help", "help", &km.CloseHelp},
+		{"yank", "visual", &km.Yank},
+		{"export", "visual", &km.Export},
+		{"keep_selected", "visual", &km.KeepSelected},
+		{"close_visual", "visual", &km.CloseVisual},
+		{"next_hidden", "columns", &km.NextHidden},
+		{"previous_hidden", "columns", &km.PrevHidden},
+		{"show_column", "columns", &km.ShowColumn},
+		{"close_columns", "columns", &km.CloseColumns},
+	}
+}
+
+func findKeyAction(actions []keyAction, name string) (keyAction, bool) {
+	for _, a := range actions {
+		if a.name == name {
+			return a, true
+		}
+	}
+	return keyAction{}, false
+}
+
+func checkConflicts(actions []keyAction) error {
+	for _, screen := range keyScreens {
+		bound := make(map[string]string)
+		for _, a := range actions {
+			if !containsString(screen, a.group) {
+				continue
+			}
+			for _, k := range a.binding.Keys() {
+				if other, ok := bound[k]; ok && other != a.name {
+					return fmt.Errorf("key %q is bound to both %q and %q", k, other, a.name)
+				}
+				bound[k] = a.name
+			}
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// keyNames holds the names of the special keys known to Bubble Tea.
+var keyNames = func() map[string]bool {
+	names := make(map[string]bool)
+	for t := tea.KeyType(-256); t < 256; t++ {
+		if name := t.String(); name != "" && name != "runes" {
+			names[name] = true
+		}
+	}
+	return names
+}()
+
+// validKey reports whether k is a single character or the name of a special
+// key, optionally with the alt modifier.
+func validKey(k string) bool {
+	k = strings.TrimPrefix(k, "alt+")
+	return utf8.RuneCountInString(k) == 1 || keyNames[k]
+}
+
+// keysHelp returns the help label of a list of keys.
+func keysHelp(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		if k == " " {
+			k = "space"
+		}
+		labels[i] = k
+	}
+	return strings.Join(labels, "/")
+}
+
+// helpKeys is the help.KeyMap of one mode of the model.
+type helpKeys struct {
+	short []key.Binding
+	full  [][]key.Binding
+}
+
+func (h helpKeys) ShortHelp() []key.Binding {
+	return h.short
+}
+
+func (h helpKeys) FullHelp() [][]key.Binding {
+	return h.full
+}
+
+// helpKeys returns the keybindings to show in the help of the current mode.
+func (m *Model) helpKeys() helpKeys {
+	k := m.keys
+	switch m.mode {
+	case "input":
+		return helpKeys{
+			short: []key.Binding{k.NextInput, k.PrevInput, k.Submit, k.ForceQuit},
+		}
+	case "detail":
+		return helpKeys{
+			short: []key.Binding{k.ScrollDetail, k.ToggleDetail, k.CloseDetail},
+		}
+	case "search", "filter", "format", "jump", "edit", "export":
+		short := []key.Binding{k.Confirm, k.Cancel}
+		if m.mode == "search" {
+			short = append(short, k.ToggleRegex)
+		}
+		return helpKeys{short: short}
+	case "global":
+		return helpKeys{
+			short: []key.Binding{k.NextHit, k.PrevHit, k.Confirm, k.Cancel, k.ToggleRegex},
+		}
+	case "columns":
+		return helpKeys{
+			short: []key.Binding{k.NextHidden, k.PrevHidden, k.ShowColumn, k.CloseColumns},
+		}
+	case "visual":
+		return helpKeys{
+			short: []key.Binding{k.Delete, k.Yank, k.Export, k.KeepSelected, k.VisualRows, k.VisualCells, k.CloseVisual},
+		}
+	default:
+		short := []key.Binding{k.Table.LineDown, k.Table.LineUp, k.Left, k.Right, k.SwitchCursorMode, k.Search, k.Filter, k.CellDetail, k.Help, k.Quit}
+		if m.mode == "help" {
+			short = []key.Binding{k.CloseHelp}
+		}
+		return helpKeys{
+			short: short,
+			full: [][]key.Binding{
+				{k.Table.LineDown, k.Table.LineUp, k.Left, k.Right, k.SwitchCursorMode},
+				{k.Table.PageDown, k.Table.PageUp, k.Table.HalfPageDown, k.Table.HalfPageUp, k.Table.GotoTop, k.Table.GotoBottom},
+				{k.NextTable, k.PrevTable, k.NewTables, k.DeleteTable, k.Quit},
+				{k.EditCell, k.EditTitle, k.InsertAfter, k.InsertBefore, k.DuplicateRow, k.Delete, k.VisualRows, k.VisualCells, k.Undo, k.Redo, k.Reset, k.SortAscending, k.SortDescending, k.Filter, k.CycleAlign, k.Format},
+				{k.WidenColumn, k.NarrowColumn, k.MoveColumnLeft, k.MoveColumnRight, k.HideColumn, k.ShowColumns},
+				{k.Search, k.SearchBackward, k.NextMatch, k.PrevMatch, k.ClearSearch, k.GlobalSearch, k.Jump},
+				{k.CellDetail, k.RowDetail, k.ToggleBorders, k.ToggleZebra, k.ToggleCompact, k.ToggleFit, k.ToggleWrap, k.ToggleNumbers, k.Transpose, k.Help},
+			},
+		}
+	}
+}
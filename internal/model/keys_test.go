@@ -0,0 +1,79 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNewKeyMap(t *testing.T) {
+	t.Run("it has no conflicts by default", func(t *testing.T) {
+		if _, err := NewKeyMap(nil); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("it overrides keys", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"column", "column2"},
+				{"test", "test"},
+				{"test2", "test2"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+
+		km, err := NewKeyMap(map[string][]string{
+			"delete":             {"ctrl+x"},
+			"switch_cursor_mode": {"ctrl+o", "m"},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		sut := NewModel(fw, WithKeyMap(km))
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlD}))
+		if len(sut.tables[0].model.Rows()) != 2 {
+			t.Errorf("expected ctrl+d to be unbound")
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("m")}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlX}))
+		if len(sut.tables[0].model.Columns()) != 1 {
+			t.Errorf("expected ctrl+x to delete a column")
+		}
+
+		if help := km.Delete.Help().Key; help != "ctrl+x" {
+			t.Errorf("expected help key ctrl+x, got %s", help)
+		}
+	})
+
+	errors := map[string]map[string][]string{
+		"unknown action":  {"explode": {"x"}},
+		"invalid key":     {"delete": {"ctrl+shift+x"}},
+		"conflict":        {"delete": {"q"}},
+		"global":          {"filter": {"ctrl+c"}},
+		"count":           {"line_down": {"5"}},
+		"visual conflict": {"yank": {"j"}},
+	}
+
+	for name, bindings := range errors {
+		t.Run("it returns error on "+name, func(t *testing.T) {
+			if _, err := NewKeyMap(bindings); err == nil {
+				t.Errorf("expected error, got nil")
+			}
+		})
+	}
+}
@@ -6,9 +6,12 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 type wiki interface {
@@ -37,25 +40,68 @@ type Model struct {
 	height   int
 	width    int
 	mode     string
+	detail   detail
+	search   search
+	global   globalSearch
+	jump     jump
+	editor   editor
+	export   export
+
+	filterInput textinput.Model
+	filterErr   error
+
+	formatInput textinput.Model
+	formatErr   error
+
+	columns columnsPanel
+
+	// count is the pending count typed before a table action, as in 10j.
+	count int
+
+	mouse mouseState
+
+	// clipboard copies text to the clipboard of the terminal.
+	clipboard func(string)
+
+	keys   KeyMap
+	theme  Theme
+	layout layout
+	help   help.Model
 }
 
-var (
-	focusedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("212"))
-	blurredStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	redStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("001"))
-	noStyle      = lipgloss.NewStyle()
+// maxCount bounds the count typed before a table action.
+const maxCount = 99999
 
-	focusedButton = focusedStyle.Copy().Render("Submit")
-	blurredButton = blurredStyle.Render("Submit")
-)
+// Option is used to set options in NewModel.
+type Option func(*Model)
+
+// WithKeyMap sets the key map.
+func WithKeyMap(km KeyMap) Option {
+	return func(m *Model) {
+		m.keys = km
+	}
+}
 
-func NewModel(wiki wiki) *Model {
+// WithTheme sets the styles of the user interface.
+func WithTheme(theme Theme) Option {
+	return func(m *Model) {
+		m.theme = theme
+	}
+}
+
+// WithClipboard sets the function that copies text to the clipboard. It
+// defaults to writing an OSC 52 escape sequence to the terminal.
+func WithClipboard(copyText func(string)) Option {
+	return func(m *Model) {
+		m.clipboard = copyText
+	}
+}
+
+func NewModel(wiki wiki, opts ...Option) *Model {
 	var inputs []textinput.Model
 
 	page := textinput.New()
 	page.Placeholder = "Arhaan_Khan"
-	page.PromptStyle = focusedStyle
-	page.TextStyle = focusedStyle
 	page.Focus()
 	inputs = append(inputs, page)
 
@@ -70,14 +116,45 @@ func NewModel(wiki wiki) *Model {
 	maxColumnWidth := textinput.New()
 	inputs = append(inputs, maxColumnWidth)
 
-	return &Model{
+	m := &Model{
 		mode: "input",
 		wiki: wiki,
 		input: input{
 			inputs: inputs,
 		},
-		index: 0,
+		search: search{
+			input: newSearchInput(),
+		},
+		global: globalSearch{
+			input: newGlobalSearchInput(),
+		},
+		jump: jump{
+			input: newJumpInput(),
+		},
+		editor: editor{
+			input: newEditInput(),
+		},
+		export: export{
+			input: newExportInput(),
+		},
+		filterInput: newFilterInput(),
+		formatInput: newFormatInput(),
+		clipboard:   termenv.Copy,
+		keys:        DefaultKeyMap(),
+		theme:       DarkTheme(),
+		help:        help.New(),
+		index:       0,
 	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	m.help.Styles = m.theme.Help
+	m.global.input.PromptStyle = m.theme.Focused
+	m.setInputFocus()
+
+	return m
 }
 
 func (m *Model) Init() tea.Cmd {
@@ -85,41 +162,43 @@ func (m *Model) Init() tea.Cmd {
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Keys can switch, edit or undo the table in the middle of a drag, and
+	// the release of the button is lost if it is let go outside the window,
+	// so a key ends any drag.
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.mouse = mouseState{}
+	}
+
 	switch m.mode {
 	case "input":
 		switch msg := msg.(type) {
 		case tea.WindowSizeMsg:
-			m.height = msg.Height
-			m.width = msg.Width
+			m.setSize(msg.Width, msg.Height)
 		case tea.KeyMsg:
-			switch msg.String() {
-			case "ctrl+c":
+			switch {
+			case key.Matches(msg, m.keys.ForceQuit):
 				return m, tea.Quit
-			case "tab", "enter", "down":
-				key := msg.String()
-
-				if key == "enter" && m.input.focus == len(m.input.inputs) {
-					data, err := m.readInput(context.Background())
-					if err != nil {
-						m.inputErr = err
-						return m, nil
-					}
-					m.inputErr = nil
-
-					m.setTables(data)
-
-					m.mode = "table"
-					m.index = 0
+			case key.Matches(msg, m.keys.Submit) && m.input.focus == len(m.input.inputs):
+				data, sources, err := m.readInput(context.Background())
+				if err != nil {
+					m.inputErr = err
 					return m, nil
 				}
+				m.inputErr = nil
+
+				m.setTables(data, sources)
 
+				m.mode = "table"
+				m.index = 0
+				return m, nil
+			case key.Matches(msg, m.keys.NextInput):
 				m.input.focus++
 				if m.input.focus > len(m.input.inputs) {
 					m.input.focus = 0
 				}
 
 				return m, m.setInputFocus()
-			case "up":
+			case key.Matches(msg, m.keys.PrevInput):
 				m.input.focus--
 				if m.input.focus < 0 {
 					m.input.focus = len(m.input.inputs)
@@ -137,65 +216,263 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case "table":
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
-			switch msg.String() {
-			case "q", "ctrl+c":
+			return m, m.updateTable(msg)
+		case tea.MouseMsg:
+			m.updateTableMouse(msg)
+		case tea.WindowSizeMsg:
+			m.setSize(msg.Width, msg.Height)
+		}
+		return m, nil
+	case "detail":
+		return m, m.updateDetail(msg)
+	case "search":
+		return m, m.updateSearch(msg)
+	case "global":
+		return m, m.updateGlobalSearch(msg)
+	case "filter":
+		return m, m.updateFilter(msg)
+	case "format":
+		return m, m.updateFormat(msg)
+	case "jump":
+		return m, m.updateJump(msg)
+	case "edit":
+		return m, m.updateEditor(msg)
+	case "columns":
+		return m, m.updateColumns(msg)
+	case "visual":
+		return m, m.updateVisual(msg)
+	case "export":
+		return m, m.updateExport(msg)
+	case "help":
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, m.keys.ForceQuit):
 				return m, tea.Quit
-			case "ctrl+n":
-				m.mode = "input"
-				m.input.focus = 0
-				return m, m.setInputFocus()
-			case "tab":
-				m.index++
-				if m.index >= len(m.tables) {
-					m.index = 0
-				}
-			case "shift+tab":
-				m.index--
-				if m.index < 0 {
-					m.index = len(m.tables) - 1
-				}
-			case "enter", "down", "j":
-				m.tables[m.index].moveDown(1)
-			case "up", "k":
-				m.tables[m.index].moveUp(1)
-			case "g":
-				m.tables[m.index].goToTop()
-			case "G":
-				m.tables[m.index].goToBottom()
-			case "ctrl+d":
-				m.tables[m.index].remove()
-			case "ctrl+k":
-				m.tables[m.index].switchCursorMode()
-			case "ctrl+r":
-				m.tables[m.index].reset(m.height - 1)
-			case "ctrl+t":
-				if m.index == 0 {
-					m.tables = m.tables[1:]
-					return m, nil
-				}
-				if m.index == len(m.tables)-1 {
-					m.tables = m.tables[:len(m.tables)-1]
-					m.index--
-					return m, nil
-				}
-				m.tables = append(m.tables[:m.index], m.tables[m.index+1:]...)
-				return m, nil
+			case key.Matches(msg, m.keys.CloseHelp):
+				m.mode = "table"
 			}
 		case tea.WindowSizeMsg:
-			m.height = msg.Height
-			m.width = msg.Width
+			m.setSize(msg.Width, msg.Height)
 		}
-		return m, nil
 	}
 	return m, nil
 }
 
+func (m *Model) updateTable(msg tea.KeyMsg) tea.Cmd {
+	t := m.tables[m.index]
+
+	if d, ok := countDigit(msg); ok && (d > 0 || m.count > 0) {
+		m.count = min(m.count*10+d, maxCount)
+		return nil
+	}
+	count := m.count
+	m.count = 0
+	n := max(count, 1)
+
+	switch {
+	case key.Matches(msg, m.keys.ForceQuit), key.Matches(msg, m.keys.Quit):
+		return tea.Quit
+	case key.Matches(msg, m.keys.NewTables):
+		m.mode = "input"
+		m.input.focus = 0
+		return m.setInputFocus()
+	case key.Matches(msg, m.keys.NextTable):
+		m.index++
+		if m.index >= len(m.tables) {
+			m.index = 0
+		}
+	case key.Matches(msg, m.keys.PrevTable):
+		m.index--
+		if m.index < 0 {
+			m.index = len(m.tables) - 1
+		}
+	case key.Matches(msg, m.keys.Left):
+		t.moveLeft(n)
+	case key.Matches(msg, m.keys.Right):
+		t.moveRight(n)
+	case key.Matches(msg, m.keys.CellDetail):
+		if m.openDetail(cellDetail) {
+			m.mode = "detail"
+		}
+	case key.Matches(msg, m.keys.RowDetail):
+		if m.openDetail(rowDetail) {
+			m.mode = "detail"
+		}
+	case key.Matches(msg, m.keys.Search):
+		m.mode = "search"
+		return m.openSearch(false)
+	case key.Matches(msg, m.keys.SearchBackward):
+		m.mode = "search"
+		return m.openSearch(true)
+	case key.Matches(msg, m.keys.Filter):
+		m.mode = "filter"
+		return m.openFilter()
+	case key.Matches(msg, m.keys.Format):
+		if len(t.data[0]) > 0 && !t.transposed {
+			m.mode = "format"
+			return m.openFormat()
+		}
+	case key.Matches(msg, m.keys.GlobalSearch):
+		m.mode = "global"
+		return m.openGlobalSearch()
+	case key.Matches(msg, m.keys.Jump):
+		m.mode = "jump"
+		return m.openJump()
+	case key.Matches(msg, m.keys.EditCell):
+		if len(t.model.Rows()) > 0 && len(t.model.Columns()) > 0 {
+			m.mode = "edit"
+			return m.openEditor(false)
+		}
+	case key.Matches(msg, m.keys.EditTitle):
+		if len(t.model.Columns()) > 0 {
+			m.mode = "edit"
+			return m.openEditor(true)
+		}
+	case key.Matches(msg, m.keys.NextMatch):
+		for i := 0; i < n; i++ {
+			m.searchNext(false)
+		}
+	case key.Matches(msg, m.keys.PrevMatch):
+		for i := 0; i < n; i++ {
+			m.searchNext(true)
+		}
+	case key.Matches(msg, m.keys.ClearSearch):
+		m.clearSearch()
+	case key.Matches(msg, m.keys.Help):
+		m.mode = "help"
+	case key.Matches(msg, m.keys.CycleAlign):
+		if len(t.cols) > 0 && !t.transposed {
+			t.cycleAlign(t.column(t.model.ColumnCursor()))
+		}
+	case key.Matches(msg, m.keys.WidenColumn):
+		t.widenColumn(n)
+	case key.Matches(msg, m.keys.NarrowColumn):
+		t.widenColumn(-n)
+	case key.Matches(msg, m.keys.MoveColumnLeft):
+		t.moveColumn(-n)
+	case key.Matches(msg, m.keys.MoveColumnRight):
+		t.moveColumn(n)
+	case key.Matches(msg, m.keys.HideColumn):
+		t.hideColumn()
+	case key.Matches(msg, m.keys.ShowColumns):
+		if m.openColumns() {
+			m.mode = "columns"
+		}
+	case key.Matches(msg, m.keys.ToggleBorders):
+		m.layout.borders = !m.layout.borders
+		m.setLayout()
+	case key.Matches(msg, m.keys.ToggleZebra):
+		m.layout.zebra = !m.layout.zebra
+		m.setLayout()
+	case key.Matches(msg, m.keys.ToggleCompact):
+		m.layout.compact = !m.layout.compact
+		m.setLayout()
+	case key.Matches(msg, m.keys.ToggleFit):
+		m.layout.fit = !m.layout.fit
+		m.setLayout()
+	case key.Matches(msg, m.keys.ToggleWrap):
+		m.layout.wrap = !m.layout.wrap
+		m.setLayout()
+	case key.Matches(msg, m.keys.ToggleNumbers):
+		m.layout.numbers = !m.layout.numbers
+		m.setLayout()
+	case key.Matches(msg, m.keys.Transpose):
+		t.transpose()
+	case key.Matches(msg, m.keys.VisualRows):
+		m.openVisual(true)
+	case key.Matches(msg, m.keys.VisualCells):
+		m.openVisual(false)
+	case count > 0 && key.Matches(msg, m.keys.Table.GotoTop, m.keys.Table.GotoBottom):
+		// 5G goes to row 5, or to column 5 in column mode.
+		t.model.SetCursor(count - 1)
+	case key.Matches(msg, m.keys.Delete):
+		t.remove()
+	case key.Matches(msg, m.keys.InsertAfter):
+		t.insert(true)
+	case key.Matches(msg, m.keys.InsertBefore):
+		t.insert(false)
+	case key.Matches(msg, m.keys.DuplicateRow):
+		t.duplicateRow()
+	case key.Matches(msg, m.keys.SwitchCursorMode):
+		t.switchCursorMode()
+	case key.Matches(msg, m.keys.SortAscending):
+		t.sort(false)
+	case key.Matches(msg, m.keys.SortDescending):
+		t.sort(true)
+	case key.Matches(msg, m.keys.Reset):
+		t.reset(m.width, m.tableHeight())
+		m.setHighlight()
+	case key.Matches(msg, m.keys.Undo):
+		for i := 0; i < n; i++ {
+			if !t.undo() {
+				break
+			}
+		}
+	case key.Matches(msg, m.keys.Redo):
+		for i := 0; i < n; i++ {
+			if !t.redo() {
+				break
+			}
+		}
+	case key.Matches(msg, m.keys.DeleteTable):
+		if m.index == 0 {
+			m.tables = m.tables[1:]
+			return nil
+		}
+		if m.index == len(m.tables)-1 {
+			m.tables = m.tables[:len(m.tables)-1]
+			m.index--
+			return nil
+		}
+		m.tables = append(m.tables[:m.index], m.tables[m.index+1:]...)
+	default:
+		// Line, page and half page movements are handled by the table.
+		t.move(msg, n)
+	}
+	return nil
+}
+
+// countDigit returns the digit typed by msg, if it is one.
+func countDigit(msg tea.KeyMsg) (int, bool) {
+	if msg.Type != tea.KeyRunes || msg.Alt || len(msg.Runes) != 1 {
+		return 0, false
+	}
+	r := msg.Runes[0]
+	if r < '0' || r > '9' {
+		return 0, false
+	}
+	return int(r - '0'), true
+}
+
 func (m *Model) View() string {
 	switch m.mode {
 	case "input":
 		return m.ViewInput()
 	case "table":
-		return m.tables[m.index].model.View()
+		return m.tableView()
+	case "detail":
+		return m.viewDetail()
+	case "search":
+		return withFooter(m.tableView(), m.viewSearch(), m.shortHelpView())
+	case "global":
+		return m.viewGlobalSearch()
+	case "filter":
+		return withFooter(m.tableView(), m.viewFilter(), m.shortHelpView())
+	case "format":
+		return withFooter(m.tableView(), m.viewFormat(), m.shortHelpView())
+	case "jump":
+		return withFooter(m.tableView(), m.viewJump(), m.shortHelpView())
+	case "edit":
+		return withFooter(m.tableView(), m.viewEditor(), m.shortHelpView())
+	case "visual":
+		return m.tableView()
+	case "export":
+		return withFooter(m.tableView(), m.viewExport(), m.shortHelpView())
+	case "columns":
+		return m.viewColumns()
+	case "help":
+		return m.viewHelp()
 	default:
 		return ""
 	}
@@ -215,25 +492,80 @@ func (m *Model) ViewInput() string {
 	b.WriteString("Maximum width of columns (leave empty for no maximum)\n")
 	b.WriteString(fmt.Sprintf("%s\n", m.input.inputs[maxColumnWidthIndex].View()))
 
-	button := blurredButton
+	button := m.theme.Blurred.Render("Submit")
 	if m.input.focus == len(m.input.inputs) {
-		button = focusedButton
+		button = m.theme.Focused.Render("Submit")
 	}
 	b.WriteString(fmt.Sprintf("\n\n%s\n\n", button))
 
 	if m.inputErr != nil {
-		b.WriteString(redStyle.Render(m.inputErr.Error()))
+		b.WriteString(m.theme.Error.Render(m.inputErr.Error()))
 	}
+	b.WriteString("\n\n")
+	b.WriteString(m.shortHelpView())
 
 	return lipgloss.NewStyle().Width(m.width).Height(m.height).Align(lipgloss.Center, lipgloss.Center).Render(b.String())
 }
 
-func (m *Model) setTables(data [][][]string) {
+func (m *Model) setTables(data [][][]string, sources []source) {
 	var tables []*table
-	for _, table := range data {
-		tables = append(tables, newTable(table, m.height-1, m.input.maxColumnWidth))
+	for i, table := range data {
+		t := newTable(table, sources[i], m.width, m.tableHeight(), m.input.maxColumnWidth)
+		t.setKeyMap(m.keys.Table)
+		t.setStyles(m.theme.Table)
+		t.setLayout(m.layout)
+		tables = append(tables, t)
 	}
 	m.tables = tables
+	m.clearSearch()
+}
+
+// tableView renders the current table with the status bar and the short help
+// under it.
+func (m *Model) tableView() string {
+	return m.tables[m.index].model.View() + "\n" + m.statusView() + "\n" + m.shortHelpView()
+}
+
+// tableHeight returns the height of the tables, leaving a line for the
+// headers, one for the status bar and one for the short help.
+func (m *Model) tableHeight() int {
+	return m.height - 3
+}
+
+// shortHelpView renders the short help of the current mode.
+func (m *Model) shortHelpView() string {
+	m.help.Width = m.width
+	return m.help.ShortHelpView(m.helpKeys().ShortHelp())
+}
+
+// viewHelp renders the full help of the table keys.
+func (m *Model) viewHelp() string {
+	m.help.Width = max(m.width-m.theme.Popup.GetHorizontalFrameSize(), 0)
+	full := m.help.FullHelpView(m.helpKeys().FullHelp())
+	view := m.theme.Title.Render("Keys") + "\n\n" + full + "\n\n" + m.help.ShortHelpView(m.helpKeys().ShortHelp())
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.Popup.Render(view))
+}
+
+// setSize sets the size of the terminal and resizes the tables to fit.
+func (m *Model) setSize(width, height int) {
+	m.width = width
+	m.height = height
+	for _, t := range m.tables {
+		t.setSize(width, m.tableHeight())
+	}
+}
+
+// setLayout sets the rendering options of every table.
+func (m *Model) setLayout() {
+	for _, t := range m.tables {
+		t.setLayout(m.layout)
+	}
+}
+
+// withFooter replaces the status bar and short help under view with a prompt.
+func withFooter(view, footer string, help string) string {
+	lines := strings.Split(view, "\n")
+	return strings.Join(append(lines[:len(lines)-2], footer, help), "\n")
 }
 
 func (m *Model) setInputFocus() tea.Cmd {
@@ -241,42 +573,42 @@ func (m *Model) setInputFocus() tea.Cmd {
 	for i := 0; i < len(m.input.inputs); i++ {
 		if i == m.input.focus {
 			cmds[i] = m.input.inputs[i].Focus()
-			m.input.inputs[i].PromptStyle = focusedStyle
-			m.input.inputs[i].TextStyle = focusedStyle
+			m.input.inputs[i].PromptStyle = m.theme.Focused
+			m.input.inputs[i].TextStyle = m.theme.Focused
 			continue
 		}
 
 		m.input.inputs[i].Blur()
-		m.input.inputs[i].PromptStyle = noStyle
-		m.input.inputs[i].TextStyle = noStyle
+		m.input.inputs[i].PromptStyle = lipgloss.NewStyle()
+		m.input.inputs[i].TextStyle = lipgloss.NewStyle()
 	}
 
 	return tea.Batch(cmds...)
 }
 
-func (m *Model) readInput(ctx context.Context) ([][][]string, error) {
+func (m *Model) readInput(ctx context.Context) ([][][]string, []source, error) {
 	var err error
 
 	page := m.input.inputs[pageIndex].Value()
 	if page == "" {
-		return nil, fmt.Errorf("invalid value: page must be set")
+		return nil, nil, fmt.Errorf("invalid value: page must be set")
 	}
 	pages := strings.Split(page, ",")
 
 	lang := m.input.inputs[langIndex].Value()
 	if lang == "" {
-		return nil, fmt.Errorf("invalid value: language code must be set")
+		return nil, nil, fmt.Errorf("invalid value: language code must be set")
 	}
 	langs := strings.Split(lang, ",")
 
 	if len(pages) != len(langs) {
-		return nil, fmt.Errorf("invalid value: number of pages and languages codes are not equal")
+		return nil, nil, fmt.Errorf("invalid value: number of pages and languages codes are not equal")
 	}
 
 	v := m.input.inputs[cleanRefIndex].Value()
 	cleanRef, err := strconv.ParseBool(v)
 	if err != nil {
-		return nil, fmt.Errorf("invalid value %v: must be true or false", v)
+		return nil, nil, fmt.Errorf("invalid value %v: must be true or false", v)
 	}
 
 	v = m.input.inputs[maxColumnWidthIndex].Value()
@@ -285,7 +617,7 @@ func (m *Model) readInput(ctx context.Context) ([][][]string, error) {
 	} else {
 		m.input.maxColumnWidth, err = strconv.Atoi(v)
 		if err != nil {
-			return nil, fmt.Errorf("invalid value %v: must be a valid number", v)
+			return nil, nil, fmt.Errorf("invalid value %v: must be a valid number", v)
 		}
 		if m.input.maxColumnWidth <= 0 {
 			m.input.maxColumnWidth = 0
@@ -293,23 +625,25 @@ func (m *Model) readInput(ctx context.Context) ([][][]string, error) {
 	}
 
 	var tables [][][]string
+	var sources []source
 	for i := range pages {
 		data, err := m.wiki.GetTablesMatrix(ctx, pages[i], langs[i], cleanRef)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		if len(data) == 0 {
-			return nil, fmt.Errorf("no tables on page %s", page)
+			return nil, nil, fmt.Errorf("no tables on page %s", page)
 		}
 
 		for _, table := range data {
 			fillRowData(table)
 			tables = append(tables, table)
+			sources = append(sources, source{page: pages[i], lang: langs[i]})
 		}
 	}
 
-	return tables, nil
+	return tables, sources, nil
 }
 
 func fillRowData(data [][]string) {
@@ -322,3 +656,21 @@ func fillRowData(data [][]string) {
 		}
 	}
 }
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func clamp(v, low, high int) int {
+	return min(max(v, low), high)
+}
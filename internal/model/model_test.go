@@ -3,7 +3,11 @@ package model
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/atye/wikitable/bubble"
@@ -515,6 +519,167 @@ func TestModel(t *testing.T) {
 		}
 	})
 
+	t.Run("it resets a table after deleting columns", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"column", "column2", "column3"},
+				{"test", "test", "test"},
+				{"test2", "test2", "test2"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlK}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlD}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlD}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlR}))
+
+		want := [][]string{
+			{"column", "column2", "column3"},
+			{"test", "test", "test"},
+			{"test2", "test2", "test2"},
+		}
+		got := modelToData(sut.tables[0].model)
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("it undoes and redoes edits", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"column", "column2", "column3"},
+				{"test", "test", "test"},
+				{"test2", "test2", "test2"},
+				{"test3", "test3", "test3"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 120, Height: 10})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		tbl := sut.tables[0]
+
+		// Delete the first row, the first column, then sort by the new
+		// first column descending.
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlD}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlK}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlD}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'S'}}))
+
+		edited := [][]string{
+			{"column2", "column3"},
+			{"test3", "test3"},
+			{"test2", "test2"},
+		}
+		if got := modelToData(tbl.model); !reflect.DeepEqual(edited, got) {
+			t.Fatalf("expected %v, got %v", edited, got)
+		}
+		if status := sut.statusView(); !strings.Contains(status, "edit 3/3") {
+			t.Errorf("expected the history position in the status, got %q", status)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'2'}}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlZ}))
+		want := [][]string{
+			{"column", "column2", "column3"},
+			{"test2", "test2", "test2"},
+			{"test3", "test3", "test3"},
+		}
+		if got := modelToData(tbl.model); !reflect.DeepEqual(want, got) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+		if status := sut.statusView(); !strings.Contains(status, "edit 1/3") {
+			t.Errorf("expected the history position in the status, got %q", status)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlY}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlY}))
+		if got := modelToData(tbl.model); !reflect.DeepEqual(edited, got) {
+			t.Errorf("expected %v, got %v", edited, got)
+		}
+
+		// Resetting is an edit that can be undone too.
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlR}))
+		if got := modelToData(tbl.model); !reflect.DeepEqual(data[0], got) {
+			t.Errorf("expected %v, got %v", data[0], got)
+		}
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlZ}))
+		if got := modelToData(tbl.model); !reflect.DeepEqual(edited, got) {
+			t.Errorf("expected %v, got %v", edited, got)
+		}
+
+		// A new edit drops the edits that were undone.
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlZ}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlD}))
+		if applied, edits := tbl.historyPosition(); applied != 3 || edits != 3 {
+			t.Errorf("expected edit 3/3, got %d/%d", applied, edits)
+		}
+	})
+
+	t.Run("it undoes deleting the only rows", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"column", "column2"},
+				{"test", "test"},
+				{"test2", "test2"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 120, Height: 10})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		tbl := sut.tables[0]
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlD}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlD}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlZ}))
+		if c := tbl.model.RowCursor(); c != 0 {
+			t.Fatalf("expected row cursor 0, got %d", c)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{'o'}}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEsc}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlD}))
+		if rows := len(tbl.model.Rows()); rows != 0 {
+			t.Errorf("expected no rows, got %d", rows)
+		}
+	})
+
 	t.Run("it uses maxColumnWidth", func(t *testing.T) {
 		data := [][][]string{
 			{
@@ -552,6 +717,1510 @@ func TestModel(t *testing.T) {
 		}
 	})
 
+	t.Run("it shows the full text of the selected cell", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"column", "column2", "column3"},
+				{"test", "a long note", "test"},
+				{"test2", "test2", "test2"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.inputs[maxColumnWidthIndex].SetValue("3")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("l")}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("o")}))
+
+		if sut.mode != "detail" {
+			t.Fatalf("expected detail mode, got %s", sut.mode)
+		}
+		if sut.detail.title != "column2" {
+			t.Errorf("expected title column2, got %s", sut.detail.title)
+		}
+		if view := sut.View(); !strings.Contains(view, "a long note") {
+			t.Errorf("expected view to contain %q, got %s", "a long note", view)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyTab}))
+		if sut.detail.view != rowDetail {
+			t.Errorf("expected row detail, got %s", sut.detail.view)
+		}
+		if view := sut.View(); !strings.Contains(view, "column3:") {
+			t.Errorf("expected view to contain %q, got %s", "column3:", view)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEsc}))
+		if sut.mode != "table" {
+			t.Errorf("expected table mode, got %s", sut.mode)
+		}
+	})
+
+	t.Run("it searches across cells", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"country", "capital", "continent"},
+				{"France", "Paris", "Europe"},
+				{"Japan", "Tokyo", "Asia"},
+				{"Peru", "Lima", "South America"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("/")}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("a")}))
+
+		assertCell := func(row, col int) {
+			t.Helper()
+			model := sut.tables[0].model
+			if model.RowCursor() != row || model.ColumnCursor() != col {
+				t.Errorf("expected cell (%d, %d), got (%d, %d)", row, col, model.RowCursor(), model.ColumnCursor())
+			}
+		}
+
+		// smart-case ignores case, so "as" skips past "France" to "Asia"
+		assertCell(0, 0)
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("s")}))
+		assertCell(1, 2)
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		if sut.mode != "table" {
+			t.Fatalf("expected table mode, got %s", sut.mode)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("n")}))
+		assertCell(1, 2)
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("/")}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("A")}))
+		assertCell(1, 2)
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("n")}))
+		assertCell(2, 2)
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("N")}))
+		assertCell(1, 2)
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("#")}))
+		if prompt := sut.search.input.Prompt; prompt != "#" {
+			t.Errorf("expected the backward search prompt #, got %q", prompt)
+		}
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlR}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("^P")}))
+		assertCell(0, 1)
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEsc}))
+		assertCell(1, 2)
+		if sut.search.re != nil {
+			t.Errorf("expected search to be cleared")
+		}
+	})
+
+	t.Run("it searches across all tables", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"country", "capital"},
+				{"France", "Paris"},
+			},
+			{
+				{"city", "population"},
+				{"Lyon", "500000"},
+				{"Paris", "2100000"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		sut.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+		// Paris is shown first but keeps its row number.
+		sut.tables[1].sortBy(0, true)
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlF}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("paris")}))
+
+		want := []hit{
+			{table: 0, row: 0, col: 1, value: "Paris", match: []int{0, 5}},
+			{table: 1, row: 0, col: 0, value: "Paris", match: []int{0, 5}},
+		}
+		if !reflect.DeepEqual(want, sut.global.hits) {
+			t.Errorf("expected %v, got %v", want, sut.global.hits)
+		}
+		if view := sut.View(); !strings.Contains(view, "table 2  row 2  city: Paris") {
+			t.Errorf("expected the hit to show its row number, got %q", view)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyDown}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+
+		if sut.mode != "table" {
+			t.Fatalf("expected table mode, got %s", sut.mode)
+		}
+		if sut.index != 1 {
+			t.Errorf("expected table index 1, got %d", sut.index)
+		}
+		model := sut.tables[1].model
+		if model.RowCursor() != 0 || model.ColumnCursor() != 0 {
+			t.Errorf("expected cell (0, 0), got (%d, %d)", model.RowCursor(), model.ColumnCursor())
+		}
+	})
+
+	t.Run("it moves through no hits across all tables", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"country", "capital"},
+				{"France", "Paris"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlF}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("zzz")}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyDown}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyUp}))
+		sut.Update(tea.WindowSizeMsg{Width: 60, Height: 8})
+
+		if sut.global.selected != 0 || sut.global.offset != 0 {
+			t.Errorf("expected no hit selected, got %d at offset %d", sut.global.selected, sut.global.offset)
+		}
+		if view := sut.View(); !strings.Contains(view, "0 matches") {
+			t.Errorf("expected no matches in the view, got %q", view)
+		}
+	})
+
+	t.Run("it sorts by columns", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"city", "country", "population"},
+				{"Lyon", "France", "522,250"},
+				{"Osaka", "Japan", "2,691,000"},
+				{"Paris", "France", "2,102,650"},
+				{"Kyoto", "Japan", ""},
+				{"Tokyo", "Japan", "13,960,000"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlK}))
+		sut.tables[0].model.SetCursor(2)
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("S")}))
+		sut.tables[0].model.SetCursor(1)
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("s")}))
+
+		want := [][]string{
+			{"city", "country", "population"},
+			{"Paris", "France", "2,102,650"},
+			{"Lyon", "France", "522,250"},
+			{"Tokyo", "Japan", "13,960,000"},
+			{"Osaka", "Japan", "2,691,000"},
+			{"Kyoto", "Japan", ""},
+		}
+		got := modelToData(sut.tables[0].model)
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+
+		columns := sut.tables[0].model.Columns()
+		if columns[1].Indicator != "▲1" || columns[2].Indicator != "▼2" {
+			t.Errorf("expected indicators ▲1 and ▼2, got %q and %q", columns[1].Indicator, columns[2].Indicator)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlK}))
+		sut.tables[0].model.SetCursor(3)
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlD}))
+
+		want = [][]string{
+			{"city", "country", "population"},
+			{"Paris", "France", "2,102,650"},
+			{"Lyon", "France", "522,250"},
+			{"Tokyo", "Japan", "13,960,000"},
+			{"Kyoto", "Japan", ""},
+		}
+		got = modelToData(sut.tables[0].model)
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("it filters rows", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"city", "country"},
+				{"Lyon", "France"},
+				{"Osaka", "Japan"},
+				{"Paris", "France"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("&")}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune(`country == "France"`)}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+
+		want := [][]string{
+			{"city", "country"},
+			{"Lyon", "France"},
+			{"Paris", "France"},
+		}
+		got := modelToData(sut.tables[0].model)
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+
+		sut.tables[0].model.SetCursor(1)
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlD}))
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("&")}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlU}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+
+		want = [][]string{
+			{"city", "country"},
+			{"Lyon", "France"},
+			{"Osaka", "Japan"},
+		}
+		got = modelToData(sut.tables[0].model)
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("it turns off filters and format rules whose column is renamed", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"city", "country"},
+				{"Lyon", "France"},
+				{"Osaka", "Japan"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 200, Height: 10})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		tbl := sut.tables[0]
+		if err := tbl.setFilter(`country == "France"`); err != nil {
+			t.Fatal(err)
+		}
+		if err := tbl.addFormatRule(0, `red if country == "France"`); err != nil {
+			t.Fatal(err)
+		}
+
+		tbl.setCell(0, 1, "nation")
+		if rows := len(tbl.model.Rows()); rows != 2 {
+			t.Errorf("expected the filter to be off, got %d rows", rows)
+		}
+		if status := sut.statusView(); !strings.Contains(status, `filter off (unknown column "country")`) {
+			t.Errorf("expected the filter error in the status bar, got %q", status)
+		}
+		if status := sut.statusView(); !strings.Contains(status, `format rule off (city: unknown column "country")`) {
+			t.Errorf("expected the format rule error in the status bar, got %q", status)
+		}
+		if rules := len(tbl.rules[0]); rules != 1 {
+			t.Errorf("expected the format rule to be kept, got %d rules", rules)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlZ}))
+		if rows := len(tbl.model.Rows()); rows != 1 {
+			t.Errorf("expected the filter to be on again, got %d rows", rows)
+		}
+		if status := sut.statusView(); strings.Contains(status, "filter off") || strings.Contains(status, "format rule off") {
+			t.Errorf("expected no errors in the status bar, got %q", status)
+		}
+	})
+
+	t.Run("it shows a status bar", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"city", "country"},
+				{"Lyon", "France"},
+				{"Osaka", "Japan"},
+				{"Paris", "France"},
+			},
+			{
+				{"column"},
+				{"test"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+
+		sut.Update(tea.WindowSizeMsg{Width: 200, Height: 10})
+		sut.input.inputs[pageIndex].SetValue("Cities")
+		sut.input.inputs[langIndex].SetValue("fr")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyDown}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlK}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("s")}))
+		sut.tables[0].setFilter(`country == "France"`)
+
+		lines := strings.Split(sut.View(), "\n")
+		if len(lines) != 10 {
+			t.Errorf("expected 10 lines, got %d", len(lines))
+		}
+
+		want := "table 1/2 │ Cities (fr) │ 2/3 rows × 2 cols │ row 1, col 1 │ column mode │ sort: city ▲ │ filter: country == \"France\""
+		if status := lines[len(lines)-2]; !strings.Contains(status, want) {
+			t.Errorf("expected status to contain %q, got %q", want, status)
+		}
+	})
+
+	t.Run("it shows help", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"column"},
+				{"test"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+
+		sut.Update(tea.WindowSizeMsg{Width: 200, Height: 40})
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		if view := sut.View(); !strings.Contains(view, "? help") {
+			t.Errorf("expected short help in view, got %s", view)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("?")}))
+		if sut.mode != "help" {
+			t.Fatalf("expected help mode, got %s", sut.mode)
+		}
+		if view := sut.View(); !strings.Contains(view, "search all tables") {
+			t.Errorf("expected full help in view, got %s", view)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEsc}))
+		if sut.mode != "table" {
+			t.Errorf("expected table mode, got %s", sut.mode)
+		}
+	})
+
+	t.Run("it moves by pages and counts", func(t *testing.T) {
+		table := [][]string{{"n", "square"}}
+		for i := 1; i <= 30; i++ {
+			table = append(table, []string{strconv.Itoa(i), strconv.Itoa(i * i)})
+		}
+		data := [][][]string{table}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 80, Height: 13})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+
+		steps := []struct {
+			keys string
+			row  int
+		}{
+			{"f", 10},
+			{"d", 15},
+			{"b", 5},
+			{"u", 0},
+			{" ", 10},
+			{"12j", 22},
+			{"10k", 12},
+			{"5G", 4},
+			{"G", 29},
+			{"100j", 29},
+			{"g", 0},
+			{"20g", 19},
+			{"2b", 0},
+			{"2d", 10},
+			{"99999j", 29},
+			{"99999k", 0},
+		}
+		for _, step := range steps {
+			for _, r := range step.keys {
+				sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{r}}))
+			}
+			if got := sut.tables[0].model.RowCursor(); got != step.row {
+				t.Errorf("expected row %d after %q, got %d", step.row, step.keys, got)
+			}
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("3")}))
+		if lines := strings.Split(sut.View(), "\n"); !strings.Contains(lines[len(lines)-2], "count 3") {
+			t.Errorf("expected pending count in status bar")
+		}
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("l")}))
+		if got := sut.tables[0].model.ColumnCursor(); got != 1 {
+			t.Errorf("expected column 1, got %d", got)
+		}
+		if sut.count != 0 {
+			t.Errorf("expected count to be reset, got %d", sut.count)
+		}
+
+		// Columns wrap around in column mode.
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlK}))
+		for _, r := range "99999j" {
+			sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{r}}))
+		}
+		if got := sut.tables[0].model.ColumnCursor(); got != 0 {
+			t.Errorf("expected column 0, got %d", got)
+		}
+	})
+
+	t.Run("it handles the mouse", func(t *testing.T) {
+		table := [][]string{{"c1", "c2", "c3", "c4"}}
+		for i := 0; i < 20; i++ {
+			cell := strings.Repeat("x", 15)
+			table = append(table, []string{cell, cell, cell, cell})
+		}
+		data := [][][]string{table}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 40, Height: 8})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		model := &sut.tables[0].model
+
+		click := func(x, y int) {
+			sut.Update(tea.MouseMsg{X: x, Y: y, Type: tea.MouseLeft})
+			sut.Update(tea.MouseMsg{X: x, Y: y, Type: tea.MouseRelease})
+		}
+
+		click(20, 3)
+		if model.RowCursor() != 2 || model.ColumnCursor() != 1 {
+			t.Errorf("expected cell 2,1 to be selected, got %d,%d", model.RowCursor(), model.ColumnCursor())
+		}
+
+		sut.Update(tea.MouseMsg{Type: tea.MouseWheelDown})
+		if model.RowCursor() != 3 || model.RowAt(1) != 3 {
+			t.Errorf("expected rows to scroll down to row 3, got cursor %d, first row %d", model.RowCursor(), model.RowAt(1))
+		}
+
+		sut.Update(tea.MouseMsg{Type: tea.MouseWheelDown, Alt: true})
+		header := strings.Split(model.View(), "\n")[0]
+		if model.ColumnAt(0) != 1 || strings.Contains(header, "c1") || !strings.Contains(header, "c2") {
+			t.Errorf("expected columns to scroll right to c2, got %q", header)
+		}
+
+		click(5, 0)
+		if model.CursorMode() != "column" || model.Cursor() != 1 {
+			t.Errorf("expected column c2 to be selected")
+		}
+		click(5, 0)
+		if model.Columns()[1].Indicator != "▲" {
+			t.Errorf("expected c2 to be sorted ascending")
+		}
+
+		sut.Update(tea.MouseMsg{X: 16, Y: 0, Type: tea.MouseLeft})
+		sut.Update(tea.MouseMsg{X: 21, Y: 0, Type: tea.MouseLeft})
+		sut.Update(tea.MouseMsg{X: 21, Y: 0, Type: tea.MouseRelease})
+		if width := model.Columns()[1].Width; width != 20 {
+			t.Errorf("expected c2 to be resized to 20, got %d", width)
+		}
+		if model.Columns()[1].Indicator != "▲" {
+			t.Errorf("expected resizing not to change the sort")
+		}
+	})
+
+	t.Run("it ends a drag when the table is switched", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"c1", "c2", "c3"},
+				{"a", "b", "c"},
+			},
+			{
+				{"c1"},
+				{"a"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 40, Height: 8})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+
+		border := -1
+		for x := 0; x < 40 && border < 0; x++ {
+			if sut.tables[0].model.BorderAt(x) == 1 {
+				border = x
+			}
+		}
+		if border < 0 {
+			t.Fatalf("expected a border for c2")
+		}
+
+		// The release of the button is never seen.
+		sut.Update(tea.MouseMsg{X: border, Y: 0, Type: tea.MouseLeft})
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyTab}))
+		if sut.index != 1 {
+			t.Fatalf("expected table 1 to be shown, got %d", sut.index)
+		}
+		width := sut.tables[1].model.Columns()[0].Width
+		sut.Update(tea.MouseMsg{X: border + 3, Y: 0, Type: tea.MouseLeft})
+
+		if got := sut.tables[1].model.Columns()[0].Width; got != width {
+			t.Errorf("expected c1 not to be resized from %d, got %d", width, got)
+		}
+		_ = sut.View()
+	})
+
+	t.Run("it toggles borders and compact layout", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"a", "b"},
+				{"1", "2"},
+				{"3", "4"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 40, Height: 8})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		model := &sut.tables[0].model
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("|")}))
+		lines := strings.Split(model.View(), "\n")
+		if !strings.Contains(lines[1], "───┼───") || !strings.Contains(lines[2], "│") {
+			t.Errorf("expected borders, got %q", lines[:3])
+		}
+		if got := len(strings.Split(sut.View(), "\n")); got != 8 {
+			t.Errorf("expected view to fit in 8 lines, got %d", got)
+		}
+		if model.RowAt(2) != 0 {
+			t.Errorf("expected first row under the rule, got %d", model.RowAt(2))
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("c")}))
+		if lines := strings.Split(model.View(), "\n"); !strings.Contains(lines[1], "─┼─") || strings.Contains(lines[1], "──") {
+			t.Errorf("expected compact borders, got %q", lines[1])
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("|")}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlR}))
+		if !model.Compact() || model.Borders() {
+			t.Errorf("expected layout to be kept after reset")
+		}
+	})
+
+	t.Run("it aligns numeric columns", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"name", "pop"},
+				{"a", "1.5"},
+				{"b", "12"},
+				{"c", "100.25"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 40, Height: 8})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("G")}))
+
+		lines := strings.Split(sut.tables[0].model.View(), "\n")
+		want := []string{" a       1.5  ", " b      12    "}
+		if !reflect.DeepEqual(want, lines[1:3]) {
+			t.Errorf("expected %q, got %q", want, lines[1:3])
+		}
+		if align := sut.tables[0].model.Columns()[0].Align; align != bubble.AlignLeft {
+			t.Errorf("expected text column to be aligned left, got %s", align)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("l")}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("a")}))
+
+		lines = strings.Split(sut.tables[0].model.View(), "\n")
+		want = []string{" a     1.5    ", " b     12     "}
+		if !reflect.DeepEqual(want, lines[1:3]) {
+			t.Errorf("expected %q, got %q", want, lines[1:3])
+		}
+	})
+
+	t.Run("it fits columns to the width", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"name", "notes", "pop"},
+				{"Tokyo", "capital of Japan and the largest metropolitan area", "13960000"},
+				{"Osaka", "port", "2691000"},
+				{"Nagoya", "industry", "2296000"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 40, Height: 8})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+
+		widths := func() []int {
+			var w []int
+			for _, col := range sut.tables[0].model.Columns() {
+				w = append(w, col.Width)
+			}
+			return w
+		}
+		if want := []int{6, 50, 8}; !reflect.DeepEqual(want, widths()) {
+			t.Fatalf("expected %v, got %v", want, widths())
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("=")}))
+		if want := []int{6, 20, 8}; !reflect.DeepEqual(want, widths()) {
+			t.Errorf("expected %v, got %v", want, widths())
+		}
+
+		sut.Update(tea.WindowSizeMsg{Width: 60, Height: 8})
+		if want := []int{6, 40, 8}; !reflect.DeepEqual(want, widths()) {
+			t.Errorf("expected %v after resizing, got %v", want, widths())
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("=")}))
+		if want := []int{6, 50, 8}; !reflect.DeepEqual(want, widths()) {
+			t.Errorf("expected %v, got %v", want, widths())
+		}
+	})
+
+	t.Run("it wraps cells", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"ep", "summary"},
+				{"1", "The crew lands on a strange planet and meets its people"},
+				{"2", "Short"},
+				{"3", "A storm strands everyone at the station for three days"},
+				{"4", "Finale"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 24, Height: 9})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		sut.tables[0].resizeColumn(1, 16)
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("w")}))
+
+		view := func() []string {
+			lines := strings.Split(ansi.ReplaceAllString(sut.tables[0].model.View(), ""), "\n")
+			return lines[1:]
+		}
+		want := []string{
+			"  1  The crew lands   ",
+			"     on a strange     ",
+			"     planet and meets ",
+			"     its people       ",
+			"  2  Short            ",
+			"                      ",
+		}
+		if !reflect.DeepEqual(want, view()) {
+			t.Errorf("expected %q, got %q", want, view())
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("G")}))
+		want = []string{
+			"  2  Short            ",
+			"  3  A storm strands  ",
+			"     everyone at the  ",
+			"     station for      ",
+			"     three days       ",
+			"  4  Finale           ",
+		}
+		if !reflect.DeepEqual(want, view()) {
+			t.Errorf("expected %q, got %q", want, view())
+		}
+
+		sut.Update(tea.MouseMsg{X: 8, Y: 4, Type: tea.MouseLeft})
+		sut.Update(tea.MouseMsg{X: 8, Y: 4, Type: tea.MouseRelease})
+		if row := sut.tables[0].model.Cursor(); row != 2 {
+			t.Errorf("expected a click on a wrapped line to select row 2, got %d", row)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune("w")}))
+		if line := view()[2]; line != "  3  A storm strands… " {
+			t.Errorf("expected cells to be cut off again, got %q", line)
+		}
+	})
+
+	t.Run("it resizes, hides and moves columns", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"a", "b", "c"},
+				{"1", "2", "3"},
+				{"4", "5", "6"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 40, Height: 8})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		tbl := sut.tables[0]
+
+		titles := func() []string {
+			var titles []string
+			for _, col := range tbl.model.Columns() {
+				titles = append(titles, col.Title)
+			}
+			return titles
+		}
+		press := func(keys string) {
+			for _, r := range keys {
+				sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{r}}))
+			}
+		}
+
+		press(">")
+		if w := tbl.model.Columns()[0].Width; w != 1 {
+			t.Errorf("expected columns not to be resized in row mode, got width %d", w)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlK}))
+		press("3>")
+		if w := tbl.model.Columns()[0].Width; w != 4 {
+			t.Errorf("expected width 4, got %d", w)
+		}
+		press("<")
+		if w := tbl.model.Columns()[0].Width; w != 3 {
+			t.Errorf("expected width 3, got %d", w)
+		}
+
+		press("L")
+		if want := []string{"b", "a", "c"}; !reflect.DeepEqual(want, titles()) {
+			t.Errorf("expected %v, got %v", want, titles())
+		}
+		if col := tbl.model.ColumnCursor(); col != 1 {
+			t.Errorf("expected the moved column to stay selected, got %d", col)
+		}
+		if w := tbl.model.Columns()[1].Width; w != 3 {
+			t.Errorf("expected the moved column to keep its width, got %d", w)
+		}
+		if want := []string{"5", "4", "6"}; !reflect.DeepEqual(want, []string(tbl.model.Rows()[1])) {
+			t.Errorf("expected %v, got %v", want, tbl.model.Rows()[1])
+		}
+
+		press("x")
+		if want := []string{"b", "c"}; !reflect.DeepEqual(want, titles()) {
+			t.Errorf("expected %v, got %v", want, titles())
+		}
+		if want := []string{"5", "6"}; !reflect.DeepEqual(want, []string(tbl.model.Rows()[1])) {
+			t.Errorf("expected %v, got %v", want, tbl.model.Rows()[1])
+		}
+
+		press("xx")
+		if want := []string{"b"}; !reflect.DeepEqual(want, titles()) {
+			t.Errorf("expected the last column not to be hidden, got %v", titles())
+		}
+
+		press("X")
+		if sut.mode != "columns" {
+			t.Fatalf("expected columns mode, got %s", sut.mode)
+		}
+		if view := sut.View(); !strings.Contains(view, "> a") || !strings.Contains(view, "  c") {
+			t.Errorf("expected the hidden columns to be listed, got %q", view)
+		}
+		press("j")
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		if want := []string{"b", "c"}; !reflect.DeepEqual(want, titles()) {
+			t.Errorf("expected %v, got %v", want, titles())
+		}
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		if sut.mode != "table" {
+			t.Errorf("expected the panel to close when no column is hidden, got %s", sut.mode)
+		}
+		if want := []string{"b", "a", "c"}; !reflect.DeepEqual(want, titles()) {
+			t.Errorf("expected %v, got %v", want, titles())
+		}
+	})
+
+	t.Run("it transposes tables", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"product", "price", "weight"},
+				{"P1", "10", "1"},
+				{"P2", "20", "2"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 100, Height: 8})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		tbl := sut.tables[0]
+
+		titles := func() []string {
+			var titles []string
+			for _, col := range tbl.model.Columns() {
+				titles = append(titles, col.Title)
+			}
+			return titles
+		}
+		rows := func() [][]string {
+			var rows [][]string
+			for _, row := range tbl.model.Rows() {
+				rows = append(rows, row)
+			}
+			return rows
+		}
+		press := func(keys string) {
+			for _, r := range keys {
+				sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{r}}))
+			}
+		}
+
+		press("T")
+		if want := []string{"product", "P1", "P2"}; !reflect.DeepEqual(want, titles()) {
+			t.Errorf("expected %v, got %v", want, titles())
+		}
+		if want := [][]string{{"price", "10", "20"}, {"weight", "1", "2"}}; !reflect.DeepEqual(want, rows()) {
+			t.Errorf("expected %v, got %v", want, rows())
+		}
+		if row, col := tbl.model.RowCursor(), tbl.model.ColumnCursor(); row != 0 || col != 1 {
+			t.Errorf("expected the selected cell at 0, 1, got %d, %d", row, col)
+		}
+		if status := sut.statusView(); !strings.Contains(status, "transposed") {
+			t.Errorf("expected the status to show the table is transposed, got %q", status)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlK}))
+		press("s")
+		if len(tbl.sortKeys) != 0 {
+			t.Errorf("expected a transposed table not to be sorted, got %v", tbl.sortKeys)
+		}
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlK}))
+
+		press("j")
+		press("T")
+		if want := []string{"product", "price", "weight"}; !reflect.DeepEqual(want, titles()) {
+			t.Errorf("expected %v, got %v", want, titles())
+		}
+		if row, col := tbl.model.RowCursor(), tbl.model.ColumnCursor(); row != 0 || col != 2 {
+			t.Errorf("expected the selected cell at 0, 2, got %d, %d", row, col)
+		}
+
+		press("T")
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlD}))
+		if want := [][]string{{"price", "10", "20"}}; !reflect.DeepEqual(want, rows()) {
+			t.Errorf("expected the weight column to be deleted, got %v", rows())
+		}
+		if want := [][]string{{"product", "price"}, {"P1", "10"}, {"P2", "20"}}; !reflect.DeepEqual(want, tbl.data) {
+			t.Errorf("expected %v, got %v", want, tbl.data)
+		}
+	})
+
+	t.Run("it numbers rows and jumps to rows and columns", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"name", "population", "population density", "area", "2"},
+				{"c", "3", "30", "300", "3000"},
+				{"a", "1", "10", "100", "1000"},
+				{"b", "2", "20", "200", "2000"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 80, Height: 8})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		tbl := sut.tables[0]
+
+		typeText := func(s string) {
+			for _, r := range s {
+				sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{r}}))
+			}
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlL}))
+		if want := []string{"1", "2", "3"}; !reflect.DeepEqual(want, tbl.model.Gutter()) {
+			t.Errorf("expected %v, got %v", want, tbl.model.Gutter())
+		}
+
+		// Sort by name, which keeps row 1 selected, and delete it.
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlK}))
+		typeText("s")
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlK}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlD}))
+		if want := []string{"2", "3"}; !reflect.DeepEqual(want, tbl.model.Gutter()) {
+			t.Errorf("expected the rows to keep their numbers, got %v", tbl.model.Gutter())
+		}
+		if view := sut.View(); !strings.Contains(view, " 2  a ") {
+			t.Errorf("expected the row numbers in the view, got %q", view)
+		}
+
+		typeText(":3")
+		if sut.mode != "jump" {
+			t.Fatalf("expected jump mode, got %s", sut.mode)
+		}
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		if row := tbl.model.RowCursor(); row != 1 {
+			t.Errorf("expected row 1 to be selected, got %d", row)
+		}
+		if sut.mode != "table" {
+			t.Errorf("expected table mode, got %s", sut.mode)
+		}
+
+		typeText("o")
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyTab}))
+		if sut.detail.title != "Row 3" {
+			t.Errorf("expected the row detail to keep the row number, got %s", sut.detail.title)
+		}
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEsc}))
+
+		typeText(":1")
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		if sut.jump.err == nil {
+			t.Errorf("expected an error for a deleted row")
+		}
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEsc}))
+
+		typeText(":popden")
+		if col := tbl.model.ColumnCursor(); col != 2 {
+			t.Errorf("expected the column to be selected as the query is typed, got %d", col)
+		}
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEsc}))
+		if col := tbl.model.ColumnCursor(); col != 0 {
+			t.Errorf("expected the column to be restored, got %d", col)
+		}
+
+		typeText(":pop")
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		if col := tbl.model.ColumnCursor(); col != 1 {
+			t.Errorf("expected the shortest matching column, got %d", col)
+		}
+
+		typeText(":`2`")
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		if col := tbl.model.ColumnCursor(); col != 4 {
+			t.Errorf("expected the column titled 2, got %d", col)
+		}
+		if row := tbl.model.RowCursor(); row != 1 {
+			t.Errorf("expected the row to be kept, got %d", row)
+		}
+
+		typeText(":2")
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		if row := tbl.model.RowCursor(); row != 0 {
+			t.Errorf("expected row 2 to be selected, got %d", row)
+		}
+	})
+
+	t.Run("it edits cells and titles", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"city", "population"},
+				{"Paris[a]", "2,100,000"},
+				{"Lyon", "520,000"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 80, Height: 8})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		tbl := sut.tables[0]
+
+		typeText := func(s string) {
+			for _, r := range s {
+				sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{r}}))
+			}
+		}
+		backspace := func(n int) {
+			for i := 0; i < n; i++ {
+				sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyBackspace}))
+			}
+		}
+
+		typeText("e")
+		if sut.mode != "edit" {
+			t.Fatalf("expected edit mode, got %s", sut.mode)
+		}
+		if w := tbl.model.Columns()[0].Width; w != minEditWidth {
+			t.Errorf("expected the column to be widened to %d, got %d", minEditWidth, w)
+		}
+		backspace(3)
+		if view := sut.View(); !strings.Contains(view, "Paris") || strings.Contains(view, "Paris[a]") {
+			t.Errorf("expected the edited value in the view, got %q", view)
+		}
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		if sut.mode != "table" {
+			t.Errorf("expected table mode, got %s", sut.mode)
+		}
+		if want := []string{"Paris", "2,100,000"}; !reflect.DeepEqual(want, tbl.data[1]) {
+			t.Errorf("expected %v, got %v", want, tbl.data[1])
+		}
+		if w := tbl.model.Columns()[0].Width; w != 5 {
+			t.Errorf("expected the column to be sized to its content, got %d", w)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlK}))
+		typeText("lE")
+		backspace(len("population"))
+		typeText("inhabitants")
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		if title := tbl.model.Columns()[1].Title; title != "inhabitants" {
+			t.Errorf("expected the title to be edited, got %q", title)
+		}
+
+		width := tbl.model.Columns()[0].Width
+		typeText("he")
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		if w := tbl.model.Columns()[0].Width; w != width {
+			t.Errorf("expected the column to be sized to its content after an unchanged edit, got %d", w)
+		}
+		typeText("l")
+
+		typeText("E")
+		typeText("!")
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEsc}))
+		if title := tbl.model.Columns()[1].Title; title != "inhabitants" {
+			t.Errorf("expected the edit to be cancelled, got %q", title)
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlZ}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlZ}))
+		if want := data[0]; !reflect.DeepEqual(want, modelToData(tbl.model)) {
+			t.Errorf("expected the edits to be undone, got %v", modelToData(tbl.model))
+		}
+	})
+
+	t.Run("it inserts rows and columns", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"city", "population"},
+				{"Paris", "2,100,000"},
+				{"Lyon", "520,000"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		tbl := sut.tables[0]
+
+		typeText := func(s string) {
+			for _, r := range s {
+				sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{r}}))
+			}
+		}
+
+		typeText("i")
+		want := [][]string{
+			{"city", "population"},
+			{"Paris", "2,100,000"},
+			{"", ""},
+			{"Lyon", "520,000"},
+		}
+		if !reflect.DeepEqual(want, modelToData(tbl.model)) {
+			t.Errorf("expected %v, got %v", want, modelToData(tbl.model))
+		}
+		if c := tbl.model.RowCursor(); c != 1 {
+			t.Errorf("expected the new row to be selected, got row %d", c)
+		}
+		if want := []int{0, 1, 3, 2}; !reflect.DeepEqual(want, tbl.ids) {
+			t.Errorf("expected ids %v, got %v", want, tbl.ids)
+		}
+
+		typeText("kD")
+		want = [][]string{
+			{"city", "population"},
+			{"Paris", "2,100,000"},
+			{"Paris", "2,100,000"},
+			{"", ""},
+			{"Lyon", "520,000"},
+		}
+		if !reflect.DeepEqual(want, modelToData(tbl.model)) {
+			t.Errorf("expected %v, got %v", want, modelToData(tbl.model))
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlK}))
+		typeText("iI")
+		want = [][]string{
+			{"city", "", "", "population"},
+			{"Paris", "", "", "2,100,000"},
+			{"Paris", "", "", "2,100,000"},
+			{"", "", "", ""},
+			{"Lyon", "", "", "520,000"},
+		}
+		if !reflect.DeepEqual(want, modelToData(tbl.model)) {
+			t.Errorf("expected %v, got %v", want, modelToData(tbl.model))
+		}
+		if c := tbl.model.ColumnCursor(); c != 1 {
+			t.Errorf("expected the new column to be selected, got column %d", c)
+		}
+
+		for i := 0; i < 4; i++ {
+			sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlZ}))
+		}
+		if want := data[0]; !reflect.DeepEqual(want, modelToData(tbl.model)) {
+			t.Errorf("expected the inserts to be undone, got %v", modelToData(tbl.model))
+		}
+	})
+
+	t.Run("it selects rows and ranges of cells", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"city", "country", "population"},
+				{"Paris", "France", "2,100,000"},
+				{"Lyon", "France", "520,000"},
+				{"Berlin", "Germany", "3,700,000"},
+				{"Rome", "Italy", "2,800,000"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		var copied string
+		sut := NewModel(fw, WithClipboard(func(s string) { copied = s }))
+		sut.Update(tea.WindowSizeMsg{Width: 120, Height: 10})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		tbl := sut.tables[0]
+
+		typeText := func(s string) {
+			for _, r := range s {
+				sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{r}}))
+			}
+		}
+
+		typeText("jV2j")
+		if sut.mode != "visual" {
+			t.Fatalf("expected visual mode, got %s", sut.mode)
+		}
+		if view := sut.View(); !strings.Contains(view, "visual: 3 rows") {
+			t.Errorf("expected the selection in the status bar, got %q", view)
+		}
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlD}))
+		if sut.mode != "table" {
+			t.Errorf("expected table mode, got %s", sut.mode)
+		}
+		want := [][]string{
+			{"city", "country", "population"},
+			{"Paris", "France", "2,100,000"},
+		}
+		if !reflect.DeepEqual(want, modelToData(tbl.model)) {
+			t.Errorf("expected %v, got %v", want, modelToData(tbl.model))
+		}
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlZ}))
+		typeText("lvjly")
+		if want := "country\tpopulation\nFrance\t2,100,000\nFrance\t520,000\n"; copied != want {
+			t.Errorf("expected %q to be copied, got %q", want, copied)
+		}
+
+		typeText("ghvjlK")
+		want = [][]string{
+			{"country", "population"},
+			{"France", "2,100,000"},
+			{"France", "520,000"},
+		}
+		if !reflect.DeepEqual(want, modelToData(tbl.model)) {
+			t.Errorf("expected %v, got %v", want, modelToData(tbl.model))
+		}
+
+		typeText("ghvj")
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlD}))
+		want = [][]string{
+			{"country", "population"},
+			{"", "2,100,000"},
+			{"", "520,000"},
+		}
+		if !reflect.DeepEqual(want, modelToData(tbl.model)) {
+			t.Errorf("expected %v, got %v", want, modelToData(tbl.model))
+		}
+
+		typeText("V")
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEsc}))
+		if sut.mode != "table" || tbl.model.Visual() != "" {
+			t.Errorf("expected the selection to be cleared")
+		}
+
+		path := filepath.Join(t.TempDir(), "selection.tsv")
+		typeText("gVj")
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlS}))
+		if sut.mode != "export" {
+			t.Fatalf("expected export mode, got %s", sut.mode)
+		}
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune(path)}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		if sut.mode != "table" {
+			t.Errorf("expected table mode, got %s", sut.mode)
+		}
+		if b, err := os.ReadFile(path); err != nil {
+			t.Errorf("expected the selection to be exported, got %v", err)
+		} else if want := "country\tpopulation\n\t2,100,000\n\t520,000\n"; string(b) != want {
+			t.Errorf("expected %q to be exported, got %q", want, string(b))
+		}
+
+		// The prompt keeps the path, which now exists.
+		typeText("gV")
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyCtrlS}))
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		if sut.mode != "export" || sut.export.err == nil {
+			t.Errorf("expected an error for an existing file")
+		}
+		if b, _ := os.ReadFile(path); string(b) != "country\tpopulation\n\t2,100,000\n\t520,000\n" {
+			t.Errorf("expected the existing file to be unchanged, got %q", string(b))
+		}
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		if sut.mode != "table" {
+			t.Errorf("expected table mode, got %s", sut.mode)
+		}
+		if b, _ := os.ReadFile(path); string(b) != "country\tpopulation\n\t2,100,000\n" {
+			t.Errorf("expected the file to be replaced once confirmed, got %q", string(b))
+		}
+	})
+
+	t.Run("it keeps the headers of a transposed table", func(t *testing.T) {
+		data := [][][]string{
+			{
+				{"name", "x", "y", "z"},
+				{"a", "1", "2", "3"},
+				{"b", "4", "5", "6"},
+			},
+		}
+
+		fw := fakeWiki{
+			GetTablesMatrixFn: func(ctx context.Context, page, lang string, cleanRef bool, tables ...int) ([][][]string, error) {
+				return data, nil
+			},
+		}
+		sut := NewModel(fw)
+		sut.Update(tea.WindowSizeMsg{Width: 120, Height: 10})
+
+		sut.input.inputs[pageIndex].SetValue("page")
+		sut.input.inputs[langIndex].SetValue("en")
+		sut.input.inputs[cleanRefIndex].SetValue("t")
+		sut.input.focus = 4
+
+		sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEnter}))
+		tbl := sut.tables[0]
+
+		for _, r := range "TjVK" {
+			sut.Update(tea.KeyMsg(tea.Key{Type: tea.KeyRunes, Runes: []rune{r}}))
+		}
+		want := [][]string{
+			{"name", "y"},
+			{"a", "2"},
+			{"b", "5"},
+		}
+		if !reflect.DeepEqual(want, tbl.data) {
+			t.Errorf("expected %v, got %v", want, tbl.data)
+		}
+	})
+
 	t.Run("it sets error on empty page", func(t *testing.T) {
 		sut := NewModel(nil)
 
@@ -0,0 +1,106 @@
+package model
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// wheelLines is the number of rows scrolled by a turn of the mouse wheel.
+const wheelLines = 3
+
+// mouseState follows the left button between mouse events, since Bubble Tea
+// reports dragging as more presses of the button.
+type mouseState struct {
+	pressed bool
+
+	// resizing is set while the border of col of table is dragged. The
+	// column was width wide when the drag started at x. resized is set once
+	// the drag has resized the column, which is a single edit.
+	resizing bool
+	resized  bool
+	table    int
+	col      int
+	x        int
+	width    int
+}
+
+// updateTableMouse handles mouse events in table mode. The table is drawn at
+// the top of the screen, starting with the headers.
+func (m *Model) updateTableMouse(msg tea.MouseMsg) {
+	t := m.tables[m.index]
+
+	switch msg.Type {
+	case tea.MouseWheelUp:
+		if msg.Alt || msg.Ctrl {
+			t.model.ScrollLeft(1)
+		} else {
+			t.model.ScrollUp(wheelLines)
+		}
+	case tea.MouseWheelDown:
+		if msg.Alt || msg.Ctrl {
+			t.model.ScrollRight(1)
+		} else {
+			t.model.ScrollDown(wheelLines)
+		}
+	case tea.MouseLeft:
+		pressed := m.mouse.pressed
+		m.mouse.pressed = true
+
+		switch {
+		case m.mouse.resizing && m.mouse.table != m.index:
+			// The table was switched during the drag.
+			m.mouse = mouseState{pressed: true}
+		case m.mouse.resizing:
+			// The border of a column is on its left in a right-to-left table.
+			delta := msg.X - m.mouse.x
+			if t.model.RTL() {
+				delta = -delta
+			}
+			if !m.mouse.resized {
+				t.edit()
+				m.mouse.resized = true
+			}
+			t.resizeColumn(m.mouse.col, m.mouse.width+delta)
+		case msg.Y == 0 && !pressed:
+			m.clickHeader(msg.X)
+		case msg.Y > 0:
+			if row := t.model.RowAt(msg.Y); row >= 0 {
+				col := t.model.ColumnAt(msg.X)
+				if col < 0 {
+					col = t.model.ColumnCursor()
+				}
+				t.model.SelectCell(row, col)
+			}
+		}
+	case tea.MouseRelease:
+		m.mouse = mouseState{}
+	}
+}
+
+// clickHeader starts resizing a column if x is on a column border. Otherwise
+// it selects the column at x, or toggles its sort if it is already selected.
+// The columns of a transposed table can't be resized or sorted.
+func (m *Model) clickHeader(x int) {
+	t := m.tables[m.index]
+
+	if col := t.model.BorderAt(x); col >= 0 && !t.transposed {
+		m.mouse.resizing = true
+		m.mouse.table = m.index
+		m.mouse.col = t.column(col)
+		m.mouse.x = x
+		m.mouse.width = t.model.Columns()[col].Width
+		return
+	}
+
+	col := t.model.ColumnAt(x)
+	if col < 0 {
+		return
+	}
+	if t.model.CursorMode() == "column" && t.model.Cursor() == col && !t.transposed {
+		t.toggleSort(t.column(col))
+		return
+	}
+	if t.model.CursorMode() != "column" {
+		t.switchCursorMode()
+	}
+	t.model.SetCursor(col)
+}
@@ -0,0 +1,195 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/atye/wikitable/bubble"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// search is an incremental search across the cells of the current table.
+// Queries are smart-case: they ignore case unless they contain an upper case
+// letter.
+type search struct {
+	input    textinput.Model
+	backward bool
+	regex    bool
+	re       *regexp.Regexp
+	err      error
+
+	// The cell selected when the prompt was opened. Incremental matches are
+	// searched from here and it is restored if the search is cancelled.
+	row int
+	col int
+}
+
+func newSearchInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = "/"
+	return input
+}
+
+// openSearch opens the search prompt, searching forward or backward from the
+// selected cell.
+func (m *Model) openSearch(backward bool) tea.Cmd {
+	t := m.tables[m.index]
+
+	m.search.backward = backward
+	m.search.row = t.model.RowCursor()
+	m.search.col = t.model.ColumnCursor()
+	m.search.input.Prompt = "/"
+	if backward {
+		m.search.input.Prompt = "#"
+	}
+	m.search.input.SetValue("")
+	m.search.re = nil
+	m.search.err = nil
+	m.setHighlight()
+
+	return m.search.input.Focus()
+}
+
+func (m *Model) updateSearch(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.ForceQuit):
+			return tea.Quit
+		case key.Matches(msg, m.keys.Confirm):
+			m.search.input.Blur()
+			m.mode = "table"
+			return nil
+		case key.Matches(msg, m.keys.Cancel):
+			m.search.input.Blur()
+			m.search.re = nil
+			m.setHighlight()
+			m.tables[m.index].model.SelectCell(m.search.row, m.search.col)
+			m.mode = "table"
+			return nil
+		case key.Matches(msg, m.keys.ToggleRegex):
+			m.search.regex = !m.search.regex
+			m.compileSearch()
+			return nil
+		}
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
+		return nil
+	}
+
+	value := m.search.input.Value()
+	var cmd tea.Cmd
+	m.search.input, cmd = m.search.input.Update(msg)
+	if m.search.input.Value() != value {
+		m.compileSearch()
+	}
+	return cmd
+}
+
+// compileSearch compiles the query and moves to the first match from where
+// the search started.
+func (m *Model) compileSearch() {
+	m.search.re, m.search.err = compileQuery(m.search.input.Value(), m.search.regex)
+	m.setHighlight()
+
+	t := m.tables[m.index]
+	t.model.SelectCell(m.search.row, m.search.col)
+	if m.search.re != nil {
+		t.findNext(m.search.re, m.search.backward, false)
+	}
+}
+
+// searchNext moves to the next match of the last search, in the direction of
+// the search unless reverse is set.
+func (m *Model) searchNext(reverse bool) {
+	if m.search.re == nil {
+		return
+	}
+	m.tables[m.index].findNext(m.search.re, m.search.backward != reverse, true)
+}
+
+func (m *Model) clearSearch() {
+	m.search.re = nil
+	m.search.err = nil
+	m.setHighlight()
+}
+
+// setHighlight highlights the matches of the current search in every table.
+func (m *Model) setHighlight() {
+	var h bubble.Highlighter
+	if re := m.search.re; re != nil {
+		h = func(value string) [][]int {
+			return re.FindAllStringIndex(value, -1)
+		}
+	}
+	for _, t := range m.tables {
+		t.model.SetHighlight(h)
+	}
+}
+
+func (m *Model) viewSearch() string {
+	view := m.search.input.View()
+	if m.search.regex {
+		view += m.theme.Blurred.Render("  (regex)")
+	}
+	if m.search.err != nil {
+		view += "  " + m.theme.Error.Render(m.search.err.Error())
+	}
+	return view
+}
+
+// compileQuery compiles a smart-case search query. Without regex the query is
+// matched literally. An empty query compiles to nil.
+func compileQuery(query string, regex bool) (*regexp.Regexp, error) {
+	if query == "" {
+		return nil, nil
+	}
+
+	expr := query
+	if !regex {
+		expr = regexp.QuoteMeta(query)
+	}
+	if !hasUpper(query) {
+		expr = "(?i)" + expr
+	}
+	return regexp.Compile(expr)
+}
+
+func hasUpper(s string) bool {
+	return strings.IndexFunc(s, unicode.IsUpper) >= 0
+}
+
+// findNext selects the next cell matching re, scanning row by row and
+// wrapping around the table. The selected cell itself is only considered if
+// skipCurrent is false. It reports whether a match was found.
+func (t *table) findNext(re *regexp.Regexp, backward bool, skipCurrent bool) bool {
+	rows := t.model.Rows()
+	numCols := len(t.model.Columns())
+	total := len(rows) * numCols
+	if total == 0 {
+		return false
+	}
+
+	start := t.model.RowCursor()*numCols + t.model.ColumnCursor()
+	step := 1
+	if backward {
+		step = -1
+	}
+
+	first := 0
+	if skipCurrent {
+		first = 1
+	}
+	for i := first; i < total+first; i++ {
+		cell := ((start+i*step)%total + total) % total
+		row, col := cell/numCols, cell%numCols
+		if re.MatchString(rows[row][col]) {
+			t.model.SelectCell(row, col)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,331 @@
+package model
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// sortKey sorts the rows of a table by a column.
+type sortKey struct {
+	col  int
+	desc bool
+}
+
+// valueKind orders values of different kinds within a sorted column.
+type valueKind int
+
+const (
+	numberKind valueKind = iota
+	dateKind
+	textKind
+	emptyKind
+)
+
+// sortValue is a cell value parsed for comparison.
+type sortValue struct {
+	kind   valueKind
+	number float64
+	text   string
+}
+
+var (
+	numberPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+
+	dateLayouts = []string{
+		"2006-01-02",
+		"2 January 2006",
+		"January 2, 2006",
+		"January 2 2006",
+		"2 Jan 2006",
+		"Jan 2, 2006",
+		"Jan 2 2006",
+		"January 2006",
+		"Jan 2006",
+		"2006-01",
+	}
+)
+
+// sortBy makes col the primary sort key of the table in the given direction.
+// The previous keys are kept as secondary keys. Sorting again by the primary
+// key in the same direction removes it.
+func (t *table) sortBy(col int, desc bool) {
+	t.edit()
+	if len(t.sortKeys) > 0 && t.sortKeys[0] == (sortKey{col: col, desc: desc}) {
+		t.sortKeys = t.sortKeys[1:]
+	} else {
+		keys := []sortKey{{col: col, desc: desc}}
+		for _, k := range t.sortKeys {
+			if k.col != col {
+				keys = append(keys, k)
+			}
+		}
+		t.sortKeys = keys
+	}
+
+	// Keep the selected row selected after sorting.
+	var selected int
+	if cursor := t.model.RowCursor(); cursor >= 0 && cursor < len(t.rows) {
+		selected = t.rows[cursor]
+	}
+	t.set()
+	t.selectRow(selected)
+}
+
+// toggleSort cycles the primary sort key of the table through col ascending,
+// col descending and unsorted by col.
+func (t *table) toggleSort(col int) {
+	desc := len(t.sortKeys) > 0 && t.sortKeys[0].col == col
+	t.sortBy(col, desc)
+}
+
+// sortRows stably sorts data rows by the table's sort keys.
+func (t *table) sortRows(rows []int) {
+	if len(t.sortKeys) == 0 {
+		return
+	}
+
+	values := make(map[int][]sortValue, len(rows))
+	for _, r := range rows {
+		v := make([]sortValue, len(t.sortKeys))
+		for i, k := range t.sortKeys {
+			v[i] = t.parsedColumn(k.col).values[r-1]
+		}
+		values[r] = v
+	}
+
+	c := collate.New(language.Make(t.source.lang), collate.IgnoreCase)
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := values[rows[i]], values[rows[j]]
+		for k, key := range t.sortKeys {
+			if n := compareValues(c, a[k], b[k], key.desc); n != 0 {
+				return n < 0
+			}
+		}
+		return false
+	})
+}
+
+// sortIndicator returns the header indicator of a sorted column. The rank of
+// the key is shown when sorting by more than one column.
+func (t *table) sortIndicator(col int) string {
+	for i, k := range t.sortKeys {
+		if k.col != col {
+			continue
+		}
+		indicator := "▲"
+		if k.desc {
+			indicator = "▼"
+		}
+		if len(t.sortKeys) > 1 {
+			indicator += strconv.Itoa(i + 1)
+		}
+		return indicator
+	}
+	return ""
+}
+
+// removeSortColumn drops the sort key of a removed column and shifts the keys
+// of the columns after it.
+func (t *table) removeSortColumn(col int) {
+	keys := t.sortKeys[:0]
+	for _, k := range t.sortKeys {
+		switch {
+		case k.col == col:
+			continue
+		case k.col > col:
+			k.col--
+		}
+		keys = append(keys, k)
+	}
+	t.sortKeys = keys
+}
+
+// insertSortColumn shifts the sort keys of the columns after a column
+// inserted at col.
+func (t *table) insertSortColumn(col int) {
+	for i, k := range t.sortKeys {
+		if k.col >= col {
+			t.sortKeys[i].col++
+		}
+	}
+}
+
+// compareValues compares two parsed cell values. Numbers sort before dates and
+// dates before text, and empty cells always sort last.
+func compareValues(c *collate.Collator, a, b sortValue, desc bool) int {
+	switch {
+	case a.kind == emptyKind && b.kind == emptyKind:
+		return 0
+	case a.kind == emptyKind:
+		return 1
+	case b.kind == emptyKind:
+		return -1
+	}
+
+	var n int
+	switch {
+	case a.kind != b.kind:
+		n = compareInts(int(a.kind), int(b.kind))
+	case a.kind == textKind:
+		n = c.CompareString(a.text, b.text)
+	case a.number < b.number:
+		n = -1
+	case a.number > b.number:
+		n = 1
+	}
+
+	if desc {
+		return -n
+	}
+	return n
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// parsedColumn is a column of a table parsed for sorting and aligning, with
+// the cells of its rows it was parsed from.
+type parsedColumn struct {
+	cells   []string
+	values  []sortValue
+	numeric bool
+}
+
+// parsedColumn returns a column of the table parsed for sorting and aligning.
+// The column is parsed again only when its cells change, so that resizing or
+// editing another column doesn't parse the whole table. The values are
+// indexed by data row, starting with the first row after the titles.
+func (t *table) parsedColumn(col int) *parsedColumn {
+	if p, ok := t.parsed[col]; ok && p.matches(t.data, col) {
+		return p
+	}
+
+	p := &parsedColumn{
+		cells:  make([]string, len(t.data)-1),
+		values: make([]sortValue, len(t.data)-1),
+	}
+	var cells, numbers int
+	for i, row := range t.data[1:] {
+		p.cells[i] = row[col]
+		p.values[i] = parseSortValue(row[col])
+		switch p.values[i].kind {
+		case emptyKind:
+			continue
+		case numberKind:
+			numbers++
+		}
+		cells++
+	}
+	p.numeric = numbers > 0 && numbers*10 >= cells*9
+
+	if t.parsed == nil {
+		t.parsed = make(map[int]*parsedColumn)
+	}
+	t.parsed[col] = p
+	return p
+}
+
+// matches reports whether p was parsed from column col of data.
+func (p *parsedColumn) matches(data [][]string, col int) bool {
+	if len(p.cells) != len(data)-1 {
+		return false
+	}
+	for i, row := range data[1:] {
+		if p.cells[i] != row[col] {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSortValue parses a cell as a number, then as a date, and otherwise
+// keeps it as text.
+func parseSortValue(s string) sortValue {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return sortValue{kind: emptyKind}
+	}
+	if n, ok := parseNumber(s); ok {
+		return sortValue{kind: numberKind, number: n}
+	}
+	if d, ok := parseDate(s); ok {
+		return sortValue{kind: dateKind, number: float64(d.Unix())}
+	}
+	return sortValue{kind: textKind, text: s}
+}
+
+// parseNumber parses numbers as written in Wikipedia tables, allowing a
+// Unicode minus, currency symbols, a percent sign and thousands separators.
+// When both '.' and ',' are used, the last one is the decimal separator. A
+// lone ',' is a decimal separator unless every group after it has three
+// digits.
+func parseNumber(s string) (float64, bool) {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '−' || r == '–':
+			return '-'
+		case unicode.Is(unicode.Sc, r), unicode.IsSpace(r), r == '%', r == '\'':
+			return -1
+		}
+		return r
+	}, s)
+
+	dot, comma := strings.LastIndex(s, "."), strings.LastIndex(s, ",")
+	switch {
+	case dot >= 0 && comma >= 0 && comma > dot:
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.Replace(s, ",", ".", 1)
+	case dot >= 0 && comma >= 0:
+		s = strings.ReplaceAll(s, ",", "")
+	case comma >= 0 && isThousandsGrouped(s, ","):
+		s = strings.ReplaceAll(s, ",", "")
+	case comma >= 0:
+		s = strings.Replace(s, ",", ".", 1)
+	case strings.Count(s, ".") > 1 && isThousandsGrouped(s, "."):
+		s = strings.ReplaceAll(s, ".", "")
+	}
+
+	if !numberPattern.MatchString(s) {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(n) {
+		return 0, false
+	}
+	return n, true
+}
+
+func isThousandsGrouped(s, sep string) bool {
+	groups := strings.Split(s, sep)
+	for _, g := range groups[1:] {
+		if len(g) != 3 {
+			return false
+		}
+	}
+	return true
+}
+
+func parseDate(s string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if d, err := time.Parse(layout, s); err == nil {
+			return d, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestParseSortValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  sortValue
+	}{
+		{"1,234,567", sortValue{kind: numberKind, number: 1234567}},
+		{"1.234.567", sortValue{kind: numberKind, number: 1234567}},
+		{"1,234.5", sortValue{kind: numberKind, number: 1234.5}},
+		{"1.234,5", sortValue{kind: numberKind, number: 1234.5}},
+		{"3,5", sortValue{kind: numberKind, number: 3.5}},
+		{"1 234 567", sortValue{kind: numberKind, number: 1234567}},
+		{"−12", sortValue{kind: numberKind, number: -12}},
+		{"45.2%", sortValue{kind: numberKind, number: 45.2}},
+		{"$1,000", sortValue{kind: numberKind, number: 1000}},
+		{"€ 2.50", sortValue{kind: numberKind, number: 2.5}},
+		{"1e6", sortValue{kind: numberKind, number: 1e6}},
+		{"1970-01-02", sortValue{kind: dateKind, number: 86400}},
+		{"2 January 1970", sortValue{kind: dateKind, number: 86400}},
+		{"January 2, 1970", sortValue{kind: dateKind, number: 86400}},
+		{"Paris", sortValue{kind: textKind, text: "Paris"}},
+		{"1990–2000", sortValue{kind: textKind, text: "1990–2000"}},
+		{"  ", sortValue{kind: emptyKind}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			got := parseSortValue(tc.value)
+			if got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParsedColumn(t *testing.T) {
+	data := [][]string{
+		{"name", "population"},
+		{"a", "1,000"},
+		{"b", "2,000"},
+	}
+	sut := newTable(data, source{}, 80, 10, 0)
+
+	parsed := sut.parsedColumn(1)
+	if !parsed.numeric {
+		t.Errorf("expected population to be numeric")
+	}
+
+	sut.resizeColumn(1, 20)
+	sut.setCell(1, 0, "c")
+	if sut.parsedColumn(1) != parsed {
+		t.Errorf("expected population not to be parsed again")
+	}
+
+	sut.setCell(1, 1, "unknown")
+	sut.setCell(2, 1, "")
+	if got := sut.parsedColumn(1); got == parsed || got.numeric || got.values[0].kind != textKind {
+		t.Errorf("expected population to be parsed again after editing it")
+	}
+}
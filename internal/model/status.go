@@ -0,0 +1,74 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// statusView renders the status bar shown under the current table.
+func (m *Model) statusView() string {
+	t := m.tables[m.index]
+
+	rows := fmt.Sprintf("%d rows", len(t.data)-1)
+	if t.filter != "" {
+		rows = fmt.Sprintf("%d/%d rows", len(t.rows), len(t.data)-1)
+	}
+	cols := fmt.Sprintf("%d cols", len(t.data[0]))
+	if len(t.cols) < len(t.data[0]) {
+		cols = fmt.Sprintf("%d/%d cols", len(t.cols), len(t.data[0]))
+	}
+
+	parts := []string{
+		fmt.Sprintf("table %d/%d", m.index+1, len(m.tables)),
+		fmt.Sprintf("%s (%s)", t.source.page, t.source.lang),
+		fmt.Sprintf("%s × %s", rows, cols),
+		fmt.Sprintf("row %d, col %d", min(t.model.RowCursor()+1, len(t.model.Rows())), t.model.ColumnCursor()+1),
+		t.model.CursorMode() + " mode",
+	}
+	if t.transposed {
+		parts = append(parts, "transposed")
+	}
+	if top, bottom, left, right, ok := t.model.Selection(); ok {
+		if t.model.Visual() == "rows" {
+			parts = append(parts, fmt.Sprintf("visual: %d rows", bottom-top+1))
+		} else {
+			parts = append(parts, fmt.Sprintf("visual: %d×%d cells", bottom-top+1, right-left+1))
+		}
+	}
+	if sort := t.sortDescription(); sort != "" {
+		parts = append(parts, "sort: "+sort)
+	}
+	if err := t.filterError(); err != nil {
+		parts = append(parts, fmt.Sprintf("filter off (%v): %s", err, t.filter))
+	} else if t.filter != "" {
+		parts = append(parts, "filter: "+t.filter)
+	}
+	if t.formatErr != nil {
+		parts = append(parts, fmt.Sprintf("format rule off (%v)", t.formatErr))
+	}
+	if applied, edits := t.historyPosition(); edits > 0 {
+		parts = append(parts, fmt.Sprintf("edit %d/%d", applied, edits))
+	}
+	if m.count > 0 {
+		parts = append(parts, fmt.Sprintf("count %d", m.count))
+	}
+
+	status := runewidth.Truncate(" "+strings.Join(parts, " │ "), max(m.width, 1), "…")
+	return m.theme.Status.Copy().Width(m.width).Render(status)
+}
+
+// sortDescription lists the sort keys of the table in order, e.g.
+// "country ▲, population ▼".
+func (t *table) sortDescription() string {
+	keys := make([]string, len(t.sortKeys))
+	for i, k := range t.sortKeys {
+		direction := "▲"
+		if k.desc {
+			direction = "▼"
+		}
+		keys[i] = t.data[0][k.col] + " " + direction
+	}
+	return strings.Join(keys, ", ")
+}
@@ -1,112 +1,379 @@
 package model
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/atye/wikitable/bubble"
-	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
 )
 
 type table struct {
 	model          bubble.Model
 	data           [][]string
-	originalData   [][]string
+	original       snapshot
+	history        history
 	maxColumnWidth int
+	source         source
+	keys           bubble.KeyMap
+	styles         bubble.Styles
+	layout         layout
+	sortKeys       []sortKey
+	filter         string
+	compiled       compiledFilter
+
+	// formatErr is the error compiling the first format rule that is off.
+	formatErr error
+
+	// parsed holds the cells of each column parsed for sorting and aligning,
+	// which are parsed again only when the column changes.
+	parsed map[int]*parsedColumn
+
+	// widths holds the width the user set for each column, or 0 to size the
+	// column to its content, and aligns the alignment the user set, or
+	// autoAlign.
+	widths []int
+	aligns []bubble.Alignment
+
+	// rules holds the format rules of each column, and hidden whether it is
+	// hidden.
+	rules  [][]formatRule
+	hidden []bool
+
+	// rows and cols hold the index in data of each row and column shown in
+	// the model.
+	rows []int
+	cols []int
+
+	// ids holds the number of each row of data in the table as it was read,
+	// which stays the same when rows are deleted, sorted or filtered.
+	ids []int
+
+	// transposed shows the rows of the table as columns.
+	transposed bool
 }
 
-func newTable(data [][]string, height, maxColumnWidth int) *table {
-	od := make([][]string, len(data))
-	copy(od, data)
+// layout holds the rendering options of the tables. fit shrinks the columns
+// sized to their content to fit the width of the terminal, and numbers shows
+// the number of each row in a gutter.
+type layout struct {
+	borders bool
+	zebra   bool
+	compact bool
+	fit     bool
+	wrap    bool
+	numbers bool
+}
+
+// source is the Wikipedia page a table was read from.
+type source struct {
+	page string
+	lang string
+}
 
-	return &table{
-		model:          generateModel(data, height, maxColumnWidth),
+// rtlLanguages holds the codes of the Wikipedias written from right to left.
+var rtlLanguages = map[string]bool{
+	"ar":  true,
+	"arz": true,
+	"azb": true,
+	"ckb": true,
+	"dv":  true,
+	"fa":  true,
+	"he":  true,
+	"ks":  true,
+	"mzn": true,
+	"pnb": true,
+	"ps":  true,
+	"sd":  true,
+	"ug":  true,
+	"ur":  true,
+	"yi":  true,
+}
+
+// rtl reports whether the source is written from right to left.
+func (s source) rtl() bool {
+	return rtlLanguages[strings.ToLower(strings.TrimSpace(s.lang))]
+}
+
+func newTable(data [][]string, src source, width, height, maxColumnWidth int) *table {
+	t := &table{
+		model:          generateModel(width, height, src.rtl()),
 		data:           data,
-		originalData:   od,
 		maxColumnWidth: maxColumnWidth,
+		source:         src,
+		widths:         make([]int, len(data[0])),
+		aligns:         newAligns(len(data[0])),
+		rules:          make([][]formatRule, len(data[0])),
+		hidden:         make([]bool, len(data[0])),
+		ids:            indexes(len(data)),
 	}
+	t.original = t.snapshot()
+	t.set()
+	return t
 }
 
+// remove deletes the row or column at the cursor.
 func (t *table) remove() {
 	cursor := t.model.Cursor()
 	switch t.model.CursorMode() {
 	case "row":
 		numRows := len(t.model.Rows())
-		t.removeRow(cursor + 1)
+		if numRows == 0 {
+			return
+		}
+		t.edit()
+		if t.transposed {
+			t.removeColumns(t.cols[cursor+1])
+		} else {
+			t.removeRows(t.rows[cursor])
+		}
 		if cursor == numRows-1 {
 			t.model.SetCursor(len(t.model.Rows()) - 1)
 		}
 	case "column":
 		numCols := len(t.model.Columns())
-		t.removeColumn(cursor)
+		if numCols == 0 {
+			return
+		}
+		switch {
+		case !t.transposed:
+			t.edit()
+			t.removeColumns(t.column(cursor))
+		case cursor > 0:
+			// The first column holds the titles of the columns.
+			t.edit()
+			t.removeRows(t.rows[cursor-1])
+		default:
+			return
+		}
 		if cursor == numCols-1 {
 			t.model.SetCursor(len(t.model.Columns()) - 1)
 		}
 	}
 }
 
-func (t *table) removeRow(row int) {
-	data := t.data
+// removeRows removes rows of data.
+func (t *table) removeRows(rows ...int) {
+	removed := indexSet(rows)
+	t.data = without(t.data, removed)
+	t.ids = without(t.ids, removed)
+	t.set()
+}
+
+// removeColumns removes columns of data, replacing its rows.
+func (t *table) removeColumns(columns ...int) {
+	removed := indexSet(columns)
+	// Columns are removed from the last one, so that the sort keys after each
+	// column are shifted once for each column removed before them.
+	for col := len(t.data[0]) - 1; col >= 0; col-- {
+		if removed[col] {
+			t.removeSortColumn(col)
+		}
+	}
 
-	if row == len(data)-1 {
-		data = data[:len(data)-1]
-	} else {
-		data = append(data[:row], data[row+1:]...)
+	data := make([][]string, len(t.data))
+	for i, row := range t.data {
+		data[i] = without(row, removed)
 	}
 
 	t.data = data
+	t.widths = without(t.widths, removed)
+	t.aligns = without(t.aligns, removed)
+	t.rules = without(t.rules, removed)
+	t.hidden = without(t.hidden, removed)
 	t.set()
 }
 
-func (t *table) removeColumn(column int) {
-	data := t.data
+// indexSet returns the set of indexes.
+func indexSet(indexes []int) map[int]bool {
+	set := make(map[int]bool, len(indexes))
+	for _, i := range indexes {
+		set[i] = true
+	}
+	return set
+}
 
-	if column == len(data[0])-1 {
-		for i, row := range data {
-			data[i] = row[:len(row)-1]
+// without returns a copy of s without the elements at the indexes removed.
+func without[T any](s []T, removed map[int]bool) []T {
+	w := make([]T, 0, len(s))
+	for i, v := range s {
+		if !removed[i] {
+			w = append(w, v)
 		}
-	} else {
-		for i, row := range data {
-			data[i] = append(row[:column], row[column+1:]...)
+	}
+	return w
+}
+
+// column returns the index in data of the column shown at col.
+func (t *table) column(col int) int {
+	return t.cols[col]
+}
+
+// dataCell returns the row and column in data of the cell of the model at row
+// and col, where row -1 is the header. The titles of the columns are row 0 of
+// data, and the first column of a transposed table.
+func (t *table) dataCell(row, col int) (int, int) {
+	if t.transposed {
+		row, col = col-1, row+1
+	}
+	if row < 0 {
+		return 0, t.cols[col]
+	}
+	return t.rows[row], t.cols[col]
+}
+
+// selectColumn selects the column of data c if it is shown.
+func (t *table) selectColumn(c int) {
+	for i, col := range t.cols {
+		if col != c {
+			continue
 		}
+		if t.transposed {
+			t.model.SelectCell(max(i-1, 0), t.model.ColumnCursor())
+		} else {
+			t.model.SelectCell(t.model.RowCursor(), i)
+		}
+		return
 	}
+}
 
-	t.data = data
-	t.set()
+// selectRow selects the row of data r if it is shown.
+func (t *table) selectRow(r int) {
+	for i, row := range t.rows {
+		if row != r {
+			continue
+		}
+		if t.transposed {
+			t.model.SelectCell(t.model.RowCursor(), i+1)
+		} else {
+			t.model.SelectCell(i, t.model.ColumnCursor())
+		}
+		return
+	}
 }
 
+// set shows the table's data in the model, showing the rows that match the
+// filter in the order of the sort keys, transposed if the table is.
 func (t *table) set() {
-	rows := make([]bubble.Row, len(t.data[1:]))
-	for i, row := range t.data[1:] {
-		rows[i] = row
+	t.rows = make([]int, len(t.data)-1)
+	for i := range t.rows {
+		t.rows[i] = i + 1
+	}
+	t.rows = t.filterRows(t.rows)
+	t.sortRows(t.rows)
+
+	t.cols = make([]int, 0, len(t.data[0]))
+	for i := range t.data[0] {
+		if !t.hidden[i] {
+			t.cols = append(t.cols, i)
+		}
+	}
+	if t.transposed {
+		t.setTransposed()
+		return
+	}
+
+	rows := make([]bubble.Row, len(t.rows))
+	for i, r := range t.rows {
+		rows[i] = t.shownRow(t.data[r])
 	}
 	t.model.SetRows(rows)
+	t.setGutter()
 
-	columns := make([]bubble.Column, len(t.data[0]))
-	for i, col := range t.data[0] {
-		colWidth := maxColumnWidth(t.data, i, t.maxColumnWidth)
+	widths := make([]int, len(t.cols))
+	for i, c := range t.cols {
+		widths[i] = maxColumnWidth(t.data, c, t.maxColumnWidth)
+		if indicator := t.sortIndicator(c); indicator != "" && t.maxColumnWidth == 0 {
+			widths[i] = max(widths[i], bubble.Width(t.data[0][c])+1+runewidth.StringWidth(indicator))
+		}
+		if t.widths[c] > 0 {
+			widths[i] = t.widths[c]
+		}
+	}
+	if t.layout.fit {
+		t.fitColumns(widths)
+	}
+
+	columns := make([]bubble.Column, len(t.cols))
+	for i, c := range t.cols {
 		columns[i] = bubble.Column{
-			Title: col,
-			Width: colWidth,
+			Title:     t.data[0][c],
+			Width:     widths[i],
+			Indicator: t.sortIndicator(c),
+			Align:     t.columnAlign(c),
 		}
 	}
 	t.model.SetColumns(columns)
+	t.setCellStyle()
+}
+
+// setGutter shows the number of each row shown in the gutter of the model if
+// row numbers are on. The rows of a transposed table are columns, which have
+// no number.
+func (t *table) setGutter() {
+	if !t.layout.numbers || t.transposed {
+		t.model.SetGutter(nil)
+		return
+	}
+	labels := make([]string, len(t.rows))
+	for i, r := range t.rows {
+		labels[i] = strconv.Itoa(t.ids[r])
+	}
+	t.model.SetGutter(labels)
 }
 
-func (t *table) moveUp(n int) {
-	t.model.MoveUp(n)
+// shownRow returns the cells of a data row in the columns shown.
+func (t *table) shownRow(row []string) []string {
+	if len(t.cols) == len(row) {
+		return row
+	}
+	shown := make([]string, len(t.cols))
+	for i, c := range t.cols {
+		shown[i] = row[c]
+	}
+	return shown
 }
 
-func (t *table) moveDown(n int) {
-	t.model.MoveDown(n)
+func (t *table) moveLeft(n int) {
+	t.model.MoveLeft(n)
 }
 
-func (t *table) goToTop() {
-	if t.model.CursorMode() == "row" {
-		t.model.GotoTop()
-	}
+func (t *table) moveRight(n int) {
+	t.model.MoveRight(n)
 }
 
-func (t *table) goToBottom() {
-	if t.model.CursorMode() == "row" {
-		t.model.GotoBottom()
+// move applies a line, page or half page movement of the table n times. Rows
+// are moved over at once, since the table is rendered after every move.
+// Columns wrap around in column mode, so only the moves that don't wrap all
+// the way around are applied.
+func (t *table) move(msg tea.KeyMsg, n int) {
+	if t.model.CursorMode() == "column" {
+		n %= max(len(t.model.Columns()), 1)
+		for i := 0; i < n; i++ {
+			t.model, _ = t.model.Update(msg)
+		}
+		return
+	}
+
+	km := t.model.KeyMap
+	page := t.model.PageSize()
+	switch {
+	case key.Matches(msg, km.LineUp):
+		t.model.MoveUp(n)
+	case key.Matches(msg, km.LineDown):
+		t.model.MoveDown(n)
+	case key.Matches(msg, km.PageUp):
+		t.model.MoveUp(n * page)
+	case key.Matches(msg, km.PageDown):
+		t.model.MoveDown(n * page)
+	case key.Matches(msg, km.HalfPageUp):
+		t.model.MoveUp(n * (page / 2))
+	case key.Matches(msg, km.HalfPageDown):
+		t.model.MoveDown(n * (page / 2))
+	default:
+		t.model, _ = t.model.Update(msg)
 	}
 }
 
@@ -114,45 +381,78 @@ func (t *table) switchCursorMode() {
 	t.model.SwitchCursorMode()
 }
 
-func (t *table) reset(height int) {
-	t.model = generateModel(t.originalData, height, t.maxColumnWidth)
-	t.data = t.originalData
+func (t *table) sort(desc bool) {
+	if t.model.CursorMode() == "column" && len(t.model.Columns()) > 0 && !t.transposed {
+		t.sortBy(t.column(t.model.Cursor()), desc)
+	}
 }
 
-func generateModel(data [][]string, height, maxColumWidth int) bubble.Model {
-	var tableRows []bubble.Row
-	var tableCols []bubble.Column
-	for rowIndex, row := range data {
-		if rowIndex == 0 {
-			for colIndex, col := range row {
-				colWidth := maxColumnWidth(data, colIndex, maxColumWidth)
-				tableCols = append(tableCols, bubble.Column{
-					Title: col,
-					Width: colWidth,
-				})
-			}
-			continue
-		}
-		tableRows = append(tableRows, row)
+func (t *table) setKeyMap(km bubble.KeyMap) {
+	t.keys = km
+	t.model.KeyMap = km
+}
+
+// resizeColumn sets the width of a column, which is no longer sized to its
+// content. A column that no longer exists is ignored.
+func (t *table) resizeColumn(col, width int) {
+	if col < 0 || col >= len(t.widths) {
+		return
+	}
+	t.widths[col] = max(width, 1)
+	t.set()
+}
+
+func (t *table) setStyles(s bubble.Styles) {
+	t.styles = s
+	t.model.SetStyles(s)
+}
+
+func (t *table) setLayout(l layout) {
+	// Borders and padding change the width left for the columns.
+	refit := l.fit || t.layout.fit
+	t.layout = l
+	t.model.SetBorders(l.borders)
+	t.model.SetZebra(l.zebra)
+	t.model.SetCompact(l.compact)
+	t.model.SetWrap(l.wrap)
+	if refit {
+		t.set()
+		return
+	}
+	t.setGutter()
+}
+
+// setSize sets the size of the table, fitting its columns to the new width.
+func (t *table) setSize(width, height int) {
+	t.model.SetWidth(width)
+	t.model.SetHeight(height)
+	if t.layout.fit {
+		t.set()
 	}
+}
 
-	model := bubble.New(bubble.WithColumns(tableCols), bubble.WithRows(tableRows), bubble.WithHeight(height))
-	s := bubble.DefaultStyles()
-	s.Selected = s.Selected.
-		Foreground(lipgloss.Color("229")).
-		Background(lipgloss.Color("57")).
-		Bold(false)
-	model.SetStyles(s)
+// reset restores the table as it was read. Like other edits, it can be
+// undone.
+func (t *table) reset(width, height int) {
+	t.edit()
+	t.model = generateModel(width, height, t.source.rtl())
+	t.model.KeyMap = t.keys
+	t.model.SetStyles(t.styles)
+	t.setLayout(t.layout)
+	t.transposed = false
+	t.restore(t.original)
+}
 
-	return model
+func generateModel(width, height int, rtl bool) bubble.Model {
+	return bubble.New(bubble.WithWidth(width), bubble.WithHeight(height), bubble.WithRTL(rtl), bubble.WithFocused(true))
 }
 
+// maxColumnWidth returns the display width of the widest cell of a column, up
+// to maxWidth if it is positive.
 func maxColumnWidth(table [][]string, col int, maxWidth int) int {
 	var width int
 	for _, row := range table {
-		if len(row[col]) > width {
-			width = len(row[col])
-		}
+		width = max(width, bubble.Width(row[col]))
 		if maxWidth > 0 && width >= maxWidth {
 			return maxWidth
 		}
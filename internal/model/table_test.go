@@ -0,0 +1,152 @@
+package model
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+)
+
+var ansi = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestColumnWidths(t *testing.T) {
+	tests := []struct {
+		name string
+		data [][]string
+		want []int
+	}{
+		{
+			name: "japanese",
+			data: [][]string{
+				{"都市", "人口"},
+				{"東京", "13,960,000"},
+				{"横浜市", "3,770,000"},
+				{"ｻｯﾎﾛ", "1,970,000"},
+			},
+			want: []int{6, 10},
+		},
+		{
+			name: "arabic",
+			data: [][]string{
+				{"المدينة", "الدولة"},
+				{"القاهرة", "مصر"},
+				{"مُحَمَّدِيَّة", "الجزائر"},
+			},
+			want: []int{7, 7},
+		},
+		{
+			name: "emoji",
+			data: [][]string{
+				{"Emoji", "Name"},
+				{"👨‍👩‍👧", "family"},
+				{"👍🏽", "thumbs up: medium skin tone"},
+				{"é́", "e with two acute accents"},
+			},
+			want: []int{5, 27},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := newTable(tc.data, source{}, 80, 10, 0)
+
+			for i, col := range sut.model.Columns() {
+				if col.Width != tc.want[i] {
+					t.Errorf("expected column %d to be %d wide, got %d", i, tc.want[i], col.Width)
+				}
+			}
+
+			lines := strings.Split(ansi.ReplaceAllString(sut.model.View(), ""), "\n")
+			want := runewidth.StringWidth(lines[0])
+			for _, line := range lines[:len(tc.data)] {
+				if w := runewidth.StringWidth(line); w != want {
+					t.Errorf("expected %q to be %d wide, got %d", line, want, w)
+				}
+			}
+		})
+	}
+
+	t.Run("it truncates by display width", func(t *testing.T) {
+		data := [][]string{
+			{"名前"},
+			{"東京都庁第一本庁舎"},
+			{"👨‍👩‍👧👨‍👩‍👧👨‍👩‍👧👨‍👩‍👧👨‍👩‍👧"},
+		}
+		sut := newTable(data, source{}, 80, 10, 7)
+
+		if w := sut.model.Columns()[0].Width; w != 7 {
+			t.Fatalf("expected column to be 7 wide, got %d", w)
+		}
+		lines := strings.Split(ansi.ReplaceAllString(sut.model.View(), ""), "\n")
+		for _, line := range lines[:len(data)] {
+			if w := runewidth.StringWidth(line); w != runewidth.StringWidth(lines[0]) {
+				t.Errorf("expected %q to be as wide as the header, got %d", line, w)
+			}
+		}
+		if !strings.Contains(lines[2], "👨‍👩‍👧👨‍👩‍👧👨‍👩‍👧…") {
+			t.Errorf("expected whole emoji sequences before the ellipsis, got %q", lines[2])
+		}
+	})
+}
+
+func TestRTL(t *testing.T) {
+	data := [][]string{
+		{"المدينة", "السكان", "ملاحظة"},
+		{"القاهرة", "9,540,000", "عاصمة (مصر)"},
+		{"الإسكندرية", "5,200,000", "ميناء 2020"},
+		{"דובאי", "3,331,000", "Dubai"},
+	}
+
+	t.Run("it lays out columns from right to left", func(t *testing.T) {
+		sut := newTable(data, source{lang: "ar"}, 50, 6, 0)
+		sut.model.SwitchCursorMode()
+
+		lines := strings.Split(ansi.ReplaceAllString(sut.model.View(), ""), "\n")
+		want := []string{
+			"                    ةظحالم     ناكسلا     ةنيدملا ",
+			"               (رصم) ةمصاع  9,540,000     ةرهاقلا ",
+			"                2020 ءانيم  5,200,000  ةيردنكسإلا ",
+			"                     Dubai  3,331,000       יאבוד ",
+		}
+		if !reflect.DeepEqual(want, lines[:4]) {
+			t.Errorf("expected\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(lines[:4], "\n"))
+		}
+
+		if col := sut.model.ColumnAt(49); col != 0 {
+			t.Errorf("expected the first column on the right, got %d", col)
+		}
+		if col := sut.model.ColumnAt(25); col != 2 {
+			t.Errorf("expected the last column on the left, got %d", col)
+		}
+		if col := sut.model.BorderAt(37); col != 0 {
+			t.Errorf("expected the border of the first column on its left, got %d", col)
+		}
+
+		sut.model.MoveLeft(1)
+		if col := sut.model.ColumnCursor(); col != 1 {
+			t.Errorf("expected moving left to select the next column, got %d", col)
+		}
+	})
+
+	t.Run("it cuts off columns on the left", func(t *testing.T) {
+		sut := newTable(data, source{lang: "he"}, 20, 6, 0)
+
+		lines := strings.Split(ansi.ReplaceAllString(sut.model.View(), ""), "\n")
+		want := []string{
+			" ناكسلا     ةنيدملا ",
+			"540,000     ةرهاقلا ",
+		}
+		if !reflect.DeepEqual(want, lines[:2]) {
+			t.Errorf("expected %q, got %q", want, lines[:2])
+		}
+	})
+
+	t.Run("it keeps left to right languages", func(t *testing.T) {
+		sut := newTable(data, source{lang: "en"}, 50, 6, 0)
+		if sut.model.RTL() {
+			t.Error("expected a left to right table")
+		}
+	})
+}
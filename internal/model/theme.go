@@ -0,0 +1,171 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/atye/wikitable/bubble"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds the styles of the user interface.
+type Theme struct {
+	Table bubble.Styles
+
+	// Focused is used for the focused input, prompts and the selected hit of
+	// a global search, and Blurred for hints.
+	Focused lipgloss.Style
+	Blurred lipgloss.Style
+	Error   lipgloss.Style
+
+	// Title and Popup are used for the titles and the box of popups.
+	Title lipgloss.Style
+	Popup lipgloss.Style
+
+	Status lipgloss.Style
+	Help   help.Styles
+}
+
+// themes holds the named themes that can be set in the config file.
+var themes = map[string]func() Theme{
+	"dark":          DarkTheme,
+	"light":         LightTheme,
+	"high-contrast": HighContrastTheme,
+	"monochrome":    MonochromeTheme,
+}
+
+// NewTheme returns the theme with the given name. The "auto" theme, or no
+// name, is monochrome if NO_COLOR is set and otherwise dark or light
+// depending on the background of the terminal.
+func NewTheme(name string) (Theme, error) {
+	switch name {
+	case "", "auto":
+		switch {
+		case os.Getenv("NO_COLOR") != "":
+			return MonochromeTheme(), nil
+		case lipgloss.HasDarkBackground():
+			return DarkTheme(), nil
+		default:
+			return LightTheme(), nil
+		}
+	}
+
+	theme, ok := themes[name]
+	if !ok {
+		names := make([]string, 0, len(themes))
+		for n := range themes {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return Theme{}, fmt.Errorf("unknown theme %q, expected auto, %s", name, strings.Join(names, ", "))
+	}
+	return theme(), nil
+}
+
+// DarkTheme returns the default theme, for terminals with a dark background.
+func DarkTheme() Theme {
+	table := bubble.DefaultStyles()
+	table.Selected = table.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+
+	return colorTheme(table, "212", "240", "001", "252", "236", help.New().Styles)
+}
+
+// LightTheme returns a theme for terminals with a light background.
+func LightTheme() Theme {
+	table := bubble.DefaultStyles()
+	table.Selected = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("16")).
+		Background(lipgloss.Color("153"))
+	table.Match = table.Match.Background(lipgloss.Color("214"))
+	table.Border = table.Border.Foreground(lipgloss.Color("250"))
+	table.Zebra = table.Zebra.Background(lipgloss.Color("254"))
+	table.Gutter = table.Gutter.Foreground(lipgloss.Color("244"))
+	table.Visual = table.Visual.Background(lipgloss.Color("189"))
+
+	return colorTheme(table, "126", "244", "160", "235", "253", help.New().Styles)
+}
+
+// HighContrastTheme returns a theme with bright colors on a dark background.
+func HighContrastTheme() Theme {
+	table := bubble.DefaultStyles()
+	table.Header = table.Header.Underline(true)
+	table.Selected = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("16")).
+		Background(lipgloss.Color("226")).
+		Bold(true)
+	table.SelectedCell = table.SelectedCell.Underline(true)
+	table.Match = table.Match.Background(lipgloss.Color("46"))
+	table.Border = table.Border.Foreground(lipgloss.Color("15"))
+	table.Zebra = table.Zebra.Background(lipgloss.Color("237"))
+	table.Gutter = table.Gutter.Foreground(lipgloss.Color("250"))
+	table.Visual = table.Visual.Background(lipgloss.Color("19"))
+
+	keys := help.New().Styles
+	keys.ShortKey = lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Bold(true)
+	keys.FullKey = keys.ShortKey
+	keys.ShortDesc = lipgloss.NewStyle().Foreground(lipgloss.Color("250"))
+	keys.FullDesc = keys.ShortDesc
+	keys.ShortSeparator = keys.ShortDesc
+	keys.FullSeparator = keys.ShortDesc
+	keys.Ellipsis = keys.ShortDesc
+
+	theme := colorTheme(table, "51", "250", "9", "16", "15", keys)
+	theme.Focused = theme.Focused.Bold(true)
+	theme.Error = theme.Error.Bold(true)
+	return theme
+}
+
+// MonochromeTheme returns a theme without colors, using only text attributes.
+func MonochromeTheme() Theme {
+	table := bubble.DefaultStyles()
+	table.Selected = lipgloss.NewStyle().Reverse(true)
+	table.SelectedCell = lipgloss.NewStyle().Reverse(false).Bold(true).Underline(true)
+	table.Match = lipgloss.NewStyle().Bold(true).Underline(true)
+	table.Border = lipgloss.NewStyle()
+	table.Zebra = lipgloss.NewStyle()
+	table.Gutter = lipgloss.NewStyle().Faint(true)
+	table.Visual = lipgloss.NewStyle().Underline(true)
+
+	keys := help.Styles{
+		ShortKey:       lipgloss.NewStyle().Bold(true),
+		ShortDesc:      lipgloss.NewStyle(),
+		ShortSeparator: lipgloss.NewStyle().Faint(true),
+		Ellipsis:       lipgloss.NewStyle().Faint(true),
+		FullKey:        lipgloss.NewStyle().Bold(true),
+		FullDesc:       lipgloss.NewStyle(),
+		FullSeparator:  lipgloss.NewStyle().Faint(true),
+	}
+
+	return Theme{
+		Table:   table,
+		Focused: lipgloss.NewStyle().Bold(true),
+		Blurred: lipgloss.NewStyle().Faint(true),
+		Error:   lipgloss.NewStyle().Bold(true).Underline(true),
+		Title:   lipgloss.NewStyle().Bold(true),
+		Popup:   lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1),
+		Status:  lipgloss.NewStyle().Reverse(true),
+		Help:    keys,
+	}
+}
+
+// colorTheme returns a theme using the accent color for focused elements and
+// popups.
+func colorTheme(table bubble.Styles, accent, muted, err, statusFg, statusBg string, keys help.Styles) Theme {
+	focused := lipgloss.NewStyle().Foreground(lipgloss.Color(accent))
+	return Theme{
+		Table:   table,
+		Focused: focused,
+		Blurred: lipgloss.NewStyle().Foreground(lipgloss.Color(muted)),
+		Error:   lipgloss.NewStyle().Foreground(lipgloss.Color(err)),
+		Title:   focused.Copy().Bold(true),
+		Popup:   lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color(accent)).Padding(0, 1),
+		Status:  lipgloss.NewStyle().Foreground(lipgloss.Color(statusFg)).Background(lipgloss.Color(statusBg)),
+		Help:    keys,
+	}
+}
@@ -0,0 +1,43 @@
+package model
+
+import "testing"
+
+func TestNewTheme(t *testing.T) {
+	for _, name := range []string{"dark", "light", "high-contrast", "monochrome"} {
+		t.Run("it returns the "+name+" theme", func(t *testing.T) {
+			if _, err := NewTheme(name); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+
+	t.Run("it is monochrome with NO_COLOR", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+
+		theme, err := NewTheme("auto")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !theme.Status.GetReverse() {
+			t.Errorf("expected monochrome theme")
+		}
+	})
+
+	t.Run("it prefers the configured theme to NO_COLOR", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+
+		theme, err := NewTheme("light")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if theme.Status.GetReverse() {
+			t.Errorf("expected light theme")
+		}
+	})
+
+	t.Run("it returns error on unknown theme", func(t *testing.T) {
+		if _, err := NewTheme("solarized"); err == nil {
+			t.Errorf("expected error, got nil")
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"github.com/atye/wikitable/bubble"
+)
+
+// transpose toggles showing the table transposed, keeping the selected cell.
+// The cell at row r and column c is at row c-1 and column r+1 once
+// transposed, and the other way around, since the titles of the columns are
+// the first column of a transposed table.
+func (t *table) transpose() {
+	row, col := t.model.RowCursor(), t.model.ColumnCursor()
+	t.transposed = !t.transposed
+	t.set()
+	t.model.SelectCell(max(col-1, 0), row+1)
+}
+
+// setTransposed shows the rows and columns selected by set in the model with
+// rows as columns: the first column holds the titles of the columns shown,
+// and each following column a row. The first column shown is the header.
+func (t *table) setTransposed() {
+	view := make([][]string, len(t.cols))
+	for i, c := range t.cols {
+		row := make([]string, 0, len(t.rows)+1)
+		row = append(row, t.data[0][c])
+		for _, r := range t.rows {
+			row = append(row, t.data[r][c])
+		}
+		view[i] = row
+	}
+	t.setGutter()
+	if len(view) == 0 {
+		t.model.SetRows(nil)
+		t.model.SetColumns(nil)
+		t.setCellStyle()
+		return
+	}
+
+	rows := make([]bubble.Row, len(view)-1)
+	for i, row := range view[1:] {
+		rows[i] = row
+	}
+	t.model.SetRows(rows)
+
+	widths := make([]int, len(view[0]))
+	for i := range widths {
+		widths[i] = maxColumnWidth(view, i, t.maxColumnWidth)
+	}
+	if t.layout.fit && t.model.Width() > 0 {
+		stats := make([]columnStats, len(widths))
+		for i, w := range widths {
+			cells := make([]string, 0, len(view)-1)
+			for _, row := range view[1:] {
+				cells = append(cells, row[i])
+			}
+			stats[i] = widthStats(cells)
+			stats[i].max = w
+		}
+		widths = fitWidths(stats, t.model.Width()-t.model.FrameWidth(len(widths)))
+	}
+
+	columns := make([]bubble.Column, len(widths))
+	for i, title := range view[0] {
+		columns[i] = bubble.Column{
+			Title: title,
+			Width: widths[i],
+			Align: bubble.AlignLeft,
+		}
+	}
+	t.model.SetColumns(columns)
+	t.setCellStyle()
+}
@@ -0,0 +1,204 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openVisual starts selecting rows of the current table from the selected
+// cell, or a range of cells if rows is false. Pressing the key of the other
+// kind of selection switches to it, and pressing the same key again stops
+// selecting.
+func (m *Model) openVisual(rows bool) {
+	t := m.tables[m.index]
+	if len(t.model.Rows()) == 0 || len(t.model.Columns()) == 0 {
+		return
+	}
+	if m.mode == "visual" && (t.model.Visual() == "rows") == rows {
+		m.closeVisual()
+		return
+	}
+	t.model.StartVisual(rows)
+	m.mode = "visual"
+}
+
+func (m *Model) closeVisual() {
+	m.tables[m.index].model.StopVisual()
+	m.count = 0
+	m.mode = "table"
+}
+
+func (m *Model) updateVisual(msg tea.Msg) tea.Cmd {
+	t := m.tables[m.index]
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		km := m.keys.Table
+		_, digit := countDigit(msg)
+		switch {
+		case key.Matches(msg, m.keys.ForceQuit):
+			return tea.Quit
+		case key.Matches(msg, m.keys.CloseVisual):
+			m.closeVisual()
+		case key.Matches(msg, m.keys.VisualRows):
+			m.openVisual(true)
+		case key.Matches(msg, m.keys.VisualCells):
+			m.openVisual(false)
+		case key.Matches(msg, m.keys.Delete):
+			t.deleteSelection()
+			m.closeVisual()
+		case key.Matches(msg, m.keys.KeepSelected):
+			t.keepSelection()
+			m.closeVisual()
+		case key.Matches(msg, m.keys.Yank):
+			m.clipboard(t.selectionText())
+			m.closeVisual()
+		case key.Matches(msg, m.keys.Export):
+			m.mode = "export"
+			return m.openExport()
+		case digit, key.Matches(msg, m.keys.Left, m.keys.Right, m.keys.SwitchCursorMode,
+			km.LineUp, km.LineDown, km.PageUp, km.PageDown, km.HalfPageUp, km.HalfPageDown, km.GotoTop, km.GotoBottom):
+			// The selection follows the cursor.
+			return m.updateTable(msg)
+		}
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
+	}
+	return nil
+}
+
+// selection returns the rows and columns of data of the cells selected in the
+// model, in the order they are shown. The titles of the columns are not part
+// of the rows.
+func (t *table) selection() ([]int, []int) {
+	top, bottom, left, right, ok := t.model.Selection()
+	if !ok {
+		return nil, nil
+	}
+	if t.transposed {
+		// The rows of a transposed table are columns of data, and its first
+		// column holds the titles of the columns.
+		top, bottom, left, right = max(left-1, 0), right-1, top+1, bottom+1
+	}
+
+	var rows, cols []int
+	for r := top; r <= bottom; r++ {
+		rows = append(rows, t.rows[r])
+	}
+	for c := left; c <= right; c++ {
+		cols = append(cols, t.cols[c])
+	}
+	return rows, cols
+}
+
+// deleteSelection deletes the selected rows, which are columns of data in a
+// transposed table, or empties the selected range of cells.
+func (t *table) deleteSelection() {
+	rows, cols := t.selection()
+	top, _, _, _, ok := t.model.Selection()
+	switch {
+	case !ok:
+		return
+	case t.model.Visual() == "rows" && t.transposed:
+		t.edit()
+		t.removeColumns(cols...)
+	case t.model.Visual() == "rows":
+		t.edit()
+		t.removeRows(rows...)
+	default:
+		t.clearCells(rows, cols)
+		return
+	}
+	t.model.SelectCell(top, t.model.ColumnCursor())
+}
+
+// keepSelection deletes the rows that are not selected, and the columns too
+// when a range of cells is selected. Rows hidden by the filter are deleted as
+// well. The titles of the columns are kept, and so is the first column of a
+// transposed table, which holds its headers.
+func (t *table) keepSelection() {
+	rows, cols := t.selection()
+	if _, _, _, _, ok := t.model.Selection(); !ok {
+		return
+	}
+	if t.transposed {
+		cols = append(cols, t.cols[0])
+	}
+	t.edit()
+	if t.model.Visual() == "cells" || !t.transposed {
+		t.removeRows(others(rows, 1, len(t.data))...)
+	}
+	if t.model.Visual() == "cells" || t.transposed {
+		t.removeColumns(others(cols, 0, len(t.data[0]))...)
+	}
+	t.model.SelectCell(0, 0)
+}
+
+// others returns the indexes from start up to end that are not in indexes.
+func others(indexes []int, start, end int) []int {
+	kept := indexSet(indexes)
+	var o []int
+	for i := start; i < end; i++ {
+		if !kept[i] {
+			o = append(o, i)
+		}
+	}
+	return o
+}
+
+// clearCells empties the cells of data in rows and cols.
+func (t *table) clearCells(rows, cols []int) {
+	if len(rows) == 0 || len(cols) == 0 {
+		return
+	}
+	t.edit()
+
+	data := append([][]string(nil), t.data...)
+	for _, r := range rows {
+		row := append([]string(nil), data[r]...)
+		for _, c := range cols {
+			row[c] = ""
+		}
+		data[r] = row
+	}
+	t.data = data
+	t.set()
+}
+
+// tsvReplacer replaces the characters that separate values and lines in
+// tab-separated values.
+var tsvReplacer = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+// selectionRecords returns the values of the selected cells, with the titles
+// of their columns first.
+func (t *table) selectionRecords() [][]string {
+	rows, cols := t.selection()
+	records := make([][]string, 0, len(rows)+1)
+	for _, r := range append([]int{0}, rows...) {
+		record := make([]string, len(cols))
+		for i, c := range cols {
+			record[i] = t.data[r][c]
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// selectionText returns the selected cells as tab-separated values, with the
+// titles of their columns on the first line, so that they can be pasted into
+// a spreadsheet.
+func (t *table) selectionText() string {
+	var b strings.Builder
+	for _, record := range t.selectionRecords() {
+		for i, value := range record {
+			if i > 0 {
+				b.WriteByte('\t')
+			}
+			b.WriteString(tsvReplacer.Replace(value))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
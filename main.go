@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/atye/wikitable/internal/config"
 	"github.com/atye/wikitable/internal/model"
 	"github.com/atye/wikitable2json/pkg/client"
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,11 +13,40 @@ import (
 
 func main() {
 	userAgent := flag.String("user-agent", "github.com/atye/wikitable", "user agent for making Wikipedia API requests")
+	configPath := flag.String("config", "", "path to the config file (default $XDG_CONFIG_HOME/wikitable/config.yaml)")
 	flag.Parse()
 
 	//log = newLogger()
 
-	if _, err := tea.NewProgram(model.NewModel(client.NewTableGetter(*userAgent)), tea.WithAltScreen()).Run(); err != nil {
+	if *configPath == "" {
+		path, err := config.Path()
+		if err != nil {
+			fmt.Println("error finding config file:", err)
+			os.Exit(1)
+		}
+		*configPath = path
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Println("error loading config:", err)
+		os.Exit(1)
+	}
+
+	keys, err := model.NewKeyMap(cfg.Keys)
+	if err != nil {
+		fmt.Printf("error loading config %s: %v\n", *configPath, err)
+		os.Exit(1)
+	}
+
+	theme, err := model.NewTheme(cfg.Theme)
+	if err != nil {
+		fmt.Printf("error loading config %s: %v\n", *configPath, err)
+		os.Exit(1)
+	}
+
+	m := model.NewModel(client.NewTableGetter(*userAgent), model.WithKeyMap(keys), model.WithTheme(theme))
+	if _, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion()).Run(); err != nil {
 		fmt.Println("error running program:", err)
 		os.Exit(1)
 	}